@@ -0,0 +1,111 @@
+package main
+
+import "fmt"
+
+// EnemyAbility habilidad especial de un tipo de enemigo
+type EnemyAbility interface {
+	Name() string
+	// Tick se llama en cada tick de juego del enemigo
+	Tick(e *Enemy)
+	// ModifyElapse permite cambiar el lapso entre pasos del enemigo
+	ModifyElapse(e *Enemy, elapse int) int
+	// Blocks indica si el enemigo trata como muro una celda aunque sea transitable
+	Blocks(celda int) bool
+}
+
+// NoAbility no hace nada, las habilidades la embeben para solo implementar lo que necesitan
+type NoAbility struct{}
+
+func (NoAbility) Name() string                          { return "" }
+func (NoAbility) Tick(e *Enemy)                         {}
+func (NoAbility) ModifyElapse(e *Enemy, elapse int) int { return elapse }
+func (NoAbility) Blocks(celda int) bool                 { return false }
+
+// DashAbility embiste cuando queda alineado con el jugador y lo puede ver
+type DashAbility struct {
+	NoAbility
+}
+
+func (a *DashAbility) Name() string {
+	return "embestida"
+}
+
+func (a *DashAbility) ModifyElapse(e *Enemy, elapse int) int {
+	jugador := e.TargetPlayer().NodePosition
+	alineado := jugador.X == e.NodePosition.X || jugador.Y == e.NodePosition.Y
+	if alineado && heuristica(e.NodePosition, jugador) <= DashRange && e.Juego.Maze.LineOfSight(e.NodePosition, jugador) {
+		return min(elapse, DashElapse)
+	}
+	return elapse
+}
+
+// IgnoreAjolotesAbility no pisa los ajolote points, sus rutas los rodean como si fueran muros
+type IgnoreAjolotesAbility struct {
+	NoAbility
+}
+
+func (a *IgnoreAjolotesAbility) Name() string {
+	return "ignora_ajolotes"
+}
+
+func (a *IgnoreAjolotesAbility) Blocks(celda int) bool {
+	return celda == AjolotePointType
+}
+
+// WallBreakerAbility cada cierto tiempo rompe un muro que lo separa del jugador
+type WallBreakerAbility struct {
+	NoAbility
+	Every       int // ticks entre cada muro roto
+	TickCounter int
+}
+
+func (a *WallBreakerAbility) Name() string {
+	return "rompe_muros"
+}
+
+func (a *WallBreakerAbility) Tick(e *Enemy) {
+	a.TickCounter++
+	if a.TickCounter < a.Every {
+		return
+	}
+
+	maze := e.Juego.Maze
+	f, c := maze.GetShape()
+	jugador := e.TargetPlayer().NodePosition
+	actual := heuristica(e.NodePosition, jugador)
+
+	for _, d := range []Pos{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+		muro := NewNode(e.NodePosition.X+d.X, e.NodePosition.Y+d.Y)
+		detras := NewNode(muro.X+d.X, muro.Y+d.Y)
+
+		// nunca rompemos el borde del mapa
+		if muro.X <= 0 || muro.Y <= 0 || muro.X >= c-1 || muro.Y >= f-1 || maze.IsWalkable(muro.X, muro.Y) {
+			continue
+		}
+
+		// solo vale la pena si del otro lado hay camino y nos acerca al jugador
+		if maze.IsWalkable(detras.X, detras.Y) && heuristica(detras, jugador) < actual {
+			maze.Set(muro.X, muro.Y, Transitable)
+			a.TickCounter = 0
+			return
+		}
+	}
+}
+
+// NewEnemyAbility crea una habilidad por su nombre, una cadena vacia indica sin habilidad
+func NewEnemyAbility(nombre string, cadaSegundos float64) (EnemyAbility, error) {
+	switch nombre {
+	case "":
+		return NoAbility{}, nil
+	case "embestida":
+		return &DashAbility{}, nil
+	case "ignora_ajolotes":
+		return &IgnoreAjolotesAbility{}, nil
+	case "rompe_muros":
+		if cadaSegundos <= 0 {
+			return nil, fmt.Errorf("la habilidad %s necesita ability_every", nombre)
+		}
+		return &WallBreakerAbility{Every: int(cadaSegundos * TPS)}, nil
+	}
+	return nil, fmt.Errorf("habilidad desconocida: %s", nombre)
+}
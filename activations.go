@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Activation funcion de activacion de una capa de la red
+type Activation interface {
+	Name() string
+	// Forward aplica la activacion a cada fila de z
+	Forward(z mat.Matrix) *mat.Dense
+	// Backward regresa el gradiente respecto a z, dado z y el gradiente respecto a la activacion
+	Backward(z, grad mat.Matrix) *mat.Dense
+	// ForwardInto y BackwardInto escriben en dst para reusar memoria al entrenar,
+	// dst debe tener las dimensiones de z y no puede ser grad
+	ForwardInto(dst *mat.Dense, z mat.Matrix)
+	BackwardInto(dst *mat.Dense, z, grad mat.Matrix)
+}
+
+// elementWise activaciones que se aplican a cada valor por separado,
+// su derivada solo depende del propio valor
+type elementWise struct {
+	nombre string
+	f      func(z float64) float64
+	df     func(z float64) float64
+}
+
+func (a elementWise) Name() string {
+	return a.nombre
+}
+
+func (a elementWise) Forward(z mat.Matrix) *mat.Dense {
+	r, c := z.Dims()
+	res := mat.NewDense(r, c, nil)
+	a.ForwardInto(res, z)
+	return res
+}
+
+func (a elementWise) ForwardInto(dst *mat.Dense, z mat.Matrix) {
+	dst.Apply(func(i, j int, v float64) float64 {
+		return a.f(v)
+	}, z)
+}
+
+// Backward grad * f'(z) elemento a elemento
+func (a elementWise) Backward(z, grad mat.Matrix) *mat.Dense {
+	r, c := z.Dims()
+	res := mat.NewDense(r, c, nil)
+	a.BackwardInto(res, z, grad)
+	return res
+}
+
+func (a elementWise) BackwardInto(dst *mat.Dense, z, grad mat.Matrix) {
+	dst.Apply(func(i, j int, v float64) float64 {
+		return grad.At(i, j) * a.df(v)
+	}, z)
+}
+
+var (
+	Sigmoid = elementWise{"sigmoide", sigmoide, d_sigmoide_z}
+
+	ReLU = elementWise{"relu",
+		func(z float64) float64 { return math.Max(0, z) },
+		func(z float64) float64 {
+			if z > 0 {
+				return 1
+			}
+			return 0
+		},
+	}
+
+	Tanh = elementWise{"tanh",
+		math.Tanh,
+		func(z float64) float64 {
+			t := math.Tanh(z)
+			return 1 - t*t
+		},
+	}
+
+	// Linear no cambia el valor, se usa en la salida de una regresion
+	Linear = elementWise{"lineal",
+		func(z float64) float64 { return z },
+		func(z float64) float64 { return 1 },
+	}
+)
+
+// LeakyReLU como ReLU pero deja pasar una fraccion de los valores negativos,
+// el nombre lleva alpha (leaky_relu:0.01) para que los modelos guardados la recuperen
+func LeakyReLU(alpha float64) Activation {
+	return elementWise{fmt.Sprintf("leaky_relu:%g", alpha),
+		func(z float64) float64 {
+			if z > 0 {
+				return z
+			}
+			return alpha * z
+		},
+		func(z float64) float64 {
+			if z > 0 {
+				return 1
+			}
+			return alpha
+		},
+	}
+}
+
+// Softmax convierte cada fila en probabilidades que suman 1, se usa en la salida de una clasificacion
+type Softmax struct{}
+
+func (Softmax) Name() string {
+	return "softmax"
+}
+
+func (s Softmax) Forward(z mat.Matrix) *mat.Dense {
+	r, c := z.Dims()
+	res := mat.NewDense(r, c, nil)
+	s.ForwardInto(res, z)
+	return res
+}
+
+func (Softmax) ForwardInto(dst *mat.Dense, z mat.Matrix) {
+	r, c := z.Dims()
+	for i := 0; i < r; i++ {
+		// restamos el maximo para que la exponencial no se desborde
+		maximo := math.Inf(-1)
+		for j := 0; j < c; j++ {
+			maximo = math.Max(maximo, z.At(i, j))
+		}
+		suma := 0.0
+		for j := 0; j < c; j++ {
+			e := math.Exp(z.At(i, j) - maximo)
+			dst.Set(i, j, e)
+			suma += e
+		}
+		for j := 0; j < c; j++ {
+			dst.Set(i, j, dst.At(i, j)/suma)
+		}
+	}
+}
+
+func (s Softmax) Backward(z, grad mat.Matrix) *mat.Dense {
+	r, c := z.Dims()
+	res := mat.NewDense(r, c, nil)
+	s.BackwardInto(res, z, grad)
+	return res
+}
+
+// BackwardInto producto con el jacobiano de softmax: s_j * (g_j - sum_k g_k s_k)
+func (s Softmax) BackwardInto(dst *mat.Dense, z, grad mat.Matrix) {
+	// dst no es grad, asi que primero guardamos ahi las probabilidades
+	s.ForwardInto(dst, z)
+	r, c := dst.Dims()
+	for i := 0; i < r; i++ {
+		punto := 0.0
+		for k := 0; k < c; k++ {
+			punto += grad.At(i, k) * dst.At(i, k)
+		}
+		for j := 0; j < c; j++ {
+			dst.Set(i, j, dst.At(i, j)*(grad.At(i, j)-punto))
+		}
+	}
+}
+
+// ActivationByName regresa la activacion por su nombre, se usa al cargar modelos
+func ActivationByName(nombre string) (Activation, error) {
+	switch nombre {
+	case "sigmoide":
+		return Sigmoid, nil
+	case "relu":
+		return ReLU, nil
+	case "leaky_relu": // sin alpha, los modelos guardados antes de que el nombre la incluyera
+		return LeakyReLU(LeakyReLUAlpha), nil
+	case "tanh":
+		return Tanh, nil
+	case "lineal":
+		return Linear, nil
+	case "softmax":
+		return Softmax{}, nil
+	}
+	if valor, ok := strings.CutPrefix(nombre, "leaky_relu:"); ok {
+		alpha, err := strconv.ParseFloat(valor, 64)
+		if err != nil {
+			return nil, fmt.Errorf("alpha invalido en %s: %v", nombre, err)
+		}
+		return LeakyReLU(alpha), nil
+	}
+	return nil, fmt.Errorf("activacion desconocida: %s", nombre)
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	pasoNumerico       = 1e-6 // desplazamiento de las diferencias finitas
+	toleranciaGradient = 1e-5 // error relativo aceptado contra el gradiente numerico
+)
+
+// matrizAleatoria valores entre -2 y 2 lejos de cero, asi no se cae en el quiebre de relu
+func matrizAleatoria(r, c int) *mat.Dense {
+	m := mat.NewDense(r, c, nil)
+	m.Apply(func(i, j int, v float64) float64 {
+		v = 0.1 + rand.Float64()*1.9
+		if rand.Intn(2) == 0 {
+			return -v
+		}
+		return v
+	}, m)
+	return m
+}
+
+// gradienteNumerico derivada de f respecto a cada valor de x con diferencias centrales,
+// f debe leer x porque se modifica en su lugar
+func gradienteNumerico(x *mat.Dense, f func() float64) *mat.Dense {
+	r, c := x.Dims()
+	g := mat.NewDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			original := x.At(i, j)
+			x.Set(i, j, original+pasoNumerico)
+			mas := f()
+			x.Set(i, j, original-pasoNumerico)
+			menos := f()
+			x.Set(i, j, original)
+			g.Set(i, j, (mas-menos)/(2*pasoNumerico))
+		}
+	}
+	return g
+}
+
+// compararMatrices falla si algun valor difiere mas que la tolerancia relativa
+func compararMatrices(t *testing.T, nombre string, obtenida, esperada mat.Matrix, tolerancia float64) {
+	t.Helper()
+	r, c := esperada.Dims()
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			o, e := obtenida.At(i, j), esperada.At(i, j)
+			escala := math.Max(1, math.Max(math.Abs(o), math.Abs(e)))
+			if math.Abs(o-e) > tolerancia*escala {
+				t.Errorf("%s[%d][%d] = %g, se esperaba %g", nombre, i, j, o, e)
+			}
+		}
+	}
+}
+
+func TestActivacionesGradiente(t *testing.T) {
+	for _, a := range []Activation{Sigmoid, ReLU, LeakyReLU(LeakyReLUAlpha), Tanh, Linear, Softmax{}} {
+		t.Run(a.Name(), func(t *testing.T) {
+			z := matrizAleatoria(3, 4)
+			g := matrizAleatoria(3, 4)
+
+			// L = sum(g * f(z)), su gradiente respecto a z es Backward(z, g)
+			perdida := func() float64 {
+				var p mat.Dense
+				p.MulElem(g, a.Forward(z))
+				return mat.Sum(&p)
+			}
+			analitico := a.Backward(z, g)
+			compararMatrices(t, "backward", analitico, gradienteNumerico(z, perdida), toleranciaGradient)
+
+			// las versiones que reusan memoria dan lo mismo
+			dst := mat.NewDense(3, 4, nil)
+			a.BackwardInto(dst, z, g)
+			compararMatrices(t, "backward_into", dst, analitico, 0)
+			a.ForwardInto(dst, z)
+			compararMatrices(t, "forward_into", dst, a.Forward(z), 0)
+		})
+	}
+}
+
+func TestActivationByName(t *testing.T) {
+	for _, a := range []Activation{Sigmoid, ReLU, LeakyReLU(LeakyReLUAlpha), Tanh, Linear, Softmax{}} {
+		b, err := ActivationByName(a.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.Name() != a.Name() {
+			t.Errorf("ActivationByName(%q) regreso %q", a.Name(), b.Name())
+		}
+	}
+	if _, err := ActivationByName("no_existe"); err == nil {
+		t.Error("se esperaba error con una activacion desconocida")
+	}
+}
+
+// al guardar un modelo solo queda el nombre de cada activacion, leaky relu debe recuperar su alpha
+func TestLeakyReLUConservaAlpha(t *testing.T) {
+	z := mat.NewDense(1, 3, []float64{-2, -0.5, 1})
+	for _, alpha := range []float64{LeakyReLUAlpha, 0.05, 0.3} {
+		original := LeakyReLU(alpha)
+		cargada, err := ActivationByName(original.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cargada.Name() != original.Name() {
+			t.Errorf("se guardo %q y se cargo %q", original.Name(), cargada.Name())
+		}
+		compararMatrices(t, original.Name(), cargada.Forward(z), original.Forward(z), 0)
+		compararMatrices(t, original.Name()+" backward", cargada.Backward(z, z), original.Backward(z, z), 0)
+	}
+
+	// los modelos guardados antes de que el nombre llevara alpha usan la de siempre
+	a, err := ActivationByName("leaky_relu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	compararMatrices(t, "leaky_relu", a.Forward(z), LeakyReLU(LeakyReLUAlpha).Forward(z), 0)
+
+	if _, err := ActivationByName("leaky_relu:abc"); err == nil {
+		t.Error("se esperaba error con un alpha invalido")
+	}
+}
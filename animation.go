@@ -55,7 +55,11 @@ func NewAnimation(options *AnimationOption) *Animation {
 }
 
 // Tick avance tick para las animaciones
+// sin ventana no se cargan animaciones, una animacion nil no hace nada
 func (a *Animation) Tick() {
+	if a == nil {
+		return
+	}
 	a.TickCounter++
 	if a.TickCounter > a.Elapse {
 		a.TickCounter = 0 // reniciamos el contador
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+)
+
+// AnimationSpec describe una animacion dentro de un archivo de datos
+type AnimationSpec struct {
+	Frames  string `json:"frames"`  // plantilla de los frames
+	Indexes [2]int `json:"indexes"` // rango de frames [inicio, fin)
+	Elapse  int    `json:"elapse"`  // ticks por frame, 0 usa EnemyAnimationElapse
+}
+
+// Load carga los frames de la animacion
+func (a *AnimationSpec) Load() *Animation {
+	elapse := a.Elapse
+	if elapse == 0 {
+		elapse = EnemyAnimationElapse
+	}
+	return NewAnimation(&AnimationOption{
+		Assets:         assetsFS,
+		Indexes:        a.Indexes,
+		TemplateString: a.Frames,
+		Elapse:         elapse,
+	})
+}
+
+func (a *AnimationSpec) validate() error {
+	if a.Frames == "" || a.Indexes[1] <= a.Indexes[0] {
+		return fmt.Errorf("animacion invalida %q %v", a.Frames, a.Indexes)
+	}
+	return nil
+}
+
+// EnemyArchetype describe un tipo de enemigo, se cargan desde assets/enemies/archetypes.json
+type EnemyArchetype struct {
+	Name    string `json:"name"`
+	Frames  string `json:"frames"`  // plantilla de los frames de su animacion caminando
+	Indexes [2]int `json:"indexes"` // rango de frames [inicio, fin)
+	// animacion quieto, si no se indica se usa el primer frame de la animacion caminando
+	Idle *AnimationSpec `json:"idle"`
+	// animaciones caminando por direccion ("arriba", "derecha", "abajo", "izquierda"),
+	// las direcciones que no tengan animacion propia voltean la animacion caminando
+	Directions      map[string]*AnimationSpec `json:"directions"`
+	FacesLeft       bool                      `json:"faces_left"` // los frames miran a la izquierda en lugar de la derecha
+	Tint            [3]float32                `json:"tint"`       // escala de color (r, g, b), todos usan los frames del perro y asi se distinguen
+	Elapse          int                       `json:"elapse"`     // lapso base cuando no hay velocidad predicha
+	ElapseDecrement int                       `json:"elapse_decrement"`
+	MinElapse       int                       `json:"min_elapse"`
+	Behavior        string                    `json:"behavior"`      // nombre del comportamiento, ver EnemyBehaviors
+	Ability         string                    `json:"ability"`       // nombre de la habilidad especial, opcional
+	AbilityEvery    float64                   `json:"ability_every"` // segundos entre usos de la habilidad, si aplica
+}
+
+// EnemyArchetypes registro de los tipos de enemigo por nombre
+var EnemyArchetypes = map[string]*EnemyArchetype{}
+
+// LoadArchetypes lee los tipos de enemigo y los agrega al registro
+func LoadArchetypes(assets embed.FS, path string) error {
+	data, err := assets.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("no se pudieron leer los tipos de enemigo: %v", err)
+	}
+
+	var tipos []*EnemyArchetype
+	if err = json.Unmarshal(data, &tipos); err != nil {
+		return fmt.Errorf("error al decodificar los tipos de enemigo: %v", err)
+	}
+
+	for _, t := range tipos {
+		if err = RegisterArchetype(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterArchetype valida un tipo de enemigo y lo agrega al registro
+func RegisterArchetype(t *EnemyArchetype) error {
+	if t.Name == "" {
+		return fmt.Errorf("tipo de enemigo sin nombre")
+	}
+	if _, ok := EnemyBehaviors[t.Behavior]; !ok {
+		return fmt.Errorf("tipo %s: comportamiento desconocido %q", t.Name, t.Behavior)
+	}
+	if _, err := NewEnemyAbility(t.Ability, t.AbilityEvery); err != nil {
+		return fmt.Errorf("tipo %s: %v", t.Name, err)
+	}
+	if err := t.Walk().validate(); err != nil {
+		return fmt.Errorf("tipo %s: %v", t.Name, err)
+	}
+	if t.Idle != nil {
+		if err := t.Idle.validate(); err != nil {
+			return fmt.Errorf("tipo %s: quieto: %v", t.Name, err)
+		}
+	}
+	for nombre, a := range t.Directions {
+		if _, ok := DirectionNames[nombre]; !ok {
+			return fmt.Errorf("tipo %s: direccion desconocida %q", t.Name, nombre)
+		}
+		if err := a.validate(); err != nil {
+			return fmt.Errorf("tipo %s: %s: %v", t.Name, nombre, err)
+		}
+	}
+
+	EnemyArchetypes[t.Name] = t
+	return nil
+}
+
+// GetArchetype busca un tipo de enemigo en el registro
+func GetArchetype(nombre string) (*EnemyArchetype, error) {
+	t, ok := EnemyArchetypes[nombre]
+	if !ok {
+		return nil, fmt.Errorf("tipo de enemigo desconocido: %s", nombre)
+	}
+	return t, nil
+}
+
+// Walk animacion caminando del tipo
+func (t *EnemyArchetype) Walk() *AnimationSpec {
+	return &AnimationSpec{Frames: t.Frames, Indexes: t.Indexes}
+}
+
+// IdleSpec animacion quieto del tipo, por defecto el primer frame caminando
+func (t *EnemyArchetype) IdleSpec() *AnimationSpec {
+	if t.Idle != nil {
+		return t.Idle
+	}
+	return &AnimationSpec{Frames: t.Frames, Indexes: [2]int{t.Indexes[0], t.Indexes[0] + 1}}
+}
+
+// Speed curva de velocidad del tipo, desplazada por el ajuste de la partida
+func (t *EnemyArchetype) Speed(ajuste int) EnemySpeed {
+	return NewEnemySpeed(t.Elapse+ajuste, t.ElapseDecrement, t.MinElapse)
+}
@@ -0,0 +1,123 @@
+package main
+
+import "math"
+
+// Autopilot jugador scriptado que se controla con la misma interfaz que el teclado,
+// va por el ajolote o power-up mas cercano sin acercarse demasiado a los perros.
+// Sirve para probar el balance y llenar la base de datos con partidas sinteticas
+type Autopilot struct {
+	Game         *Game
+	Player       *Player
+	SafeDistance int // distancia minima que intenta guardar con los perros
+
+	planTick int // tick en el que se calculo el plan
+	planned  bool
+	dir      Direction
+	move     bool
+}
+
+func NewAutopilot(j *Game, p *Player, margen int) *Autopilot {
+	return &Autopilot{Game: j, Player: p, SafeDistance: margen}
+}
+
+func (a *Autopilot) Pressed(accion Action) bool {
+	a.plan()
+	if !a.move {
+		return false
+	}
+	for _, da := range directionActions {
+		if da.Action == accion {
+			return da.Direction == a.dir
+		}
+	}
+	return false // nunca pausa ni acepta
+}
+
+// JustPressed el bot vuelve a pedir su direccion cada tick, asi siempre reemplaza a la guardada
+func (a *Autopilot) JustPressed(accion Action) bool {
+	return a.Pressed(accion)
+}
+
+// plan elige la direccion desde la celda a la que va el jugador, una vez por tick
+func (a *Autopilot) plan() {
+	j := a.Game
+	if a.planned && a.planTick == j.Ticks {
+		return
+	}
+	a.planned = true
+	a.planTick = j.Ticks
+	a.move = false
+
+	p := a.Player
+	maze := j.Maze
+
+	var metas []*Node
+	metas = append(metas, maze.Cells(AjolotePointType)...)
+	for _, pu := range PowerUps {
+		metas = append(metas, maze.Cells(pu.Cell())...)
+	}
+	campo := maze.MultiDistanceField(metas)
+
+	// solo cuentan los perros que pueden atraparlo
+	var perros []*Node
+	for _, e := range j.Enemys {
+		if !e.IsSpawning() && e.Mode() != FrightenedMode {
+			perros = append(perros, e.NodePosition)
+		}
+	}
+	var peligro [][]int
+	if len(perros) > 0 && !p.IsInvulnerable() {
+		peligro = maze.MultiDistanceField(perros)
+	}
+
+	distanciaPerro := func(x, y int) int {
+		if peligro == nil || peligro[y][x] == -1 {
+			return math.MaxInt
+		}
+		return peligro[y][x]
+	}
+	distanciaMeta := func(x, y int) int {
+		if campo[y][x] == -1 {
+			return math.MaxInt
+		}
+		return campo[y][x]
+	}
+
+	// si ya tiene un perro encima solo piensa en huir
+	huir := distanciaPerro(p.NodePosition.X, p.NodePosition.Y) < a.SafeDistance
+
+	// empezamos por la direccion actual para no titubear en los empates
+	dirs := []Direction{p.CurrentDirection}
+	for _, d := range []Direction{DirectionUp, DirectionRight, DirectionDown, DirectionLeft} {
+		if d != p.CurrentDirection {
+			dirs = append(dirs, d)
+		}
+	}
+
+	mejorSeguro, mejorHuida := -1, -1
+	var valorSeguro, valorHuida int
+	for _, d := range dirs {
+		dx, dy := d.Delta()
+		x, y := p.NodePosition.X+dx, p.NodePosition.Y+dy
+		if !p.validNode(NewNode(x, y)) {
+			continue
+		}
+
+		dp := distanciaPerro(x, y)
+		if mejorHuida == -1 || dp > valorHuida {
+			mejorHuida, valorHuida = int(d), dp
+		}
+		if dp >= a.SafeDistance {
+			if dm := distanciaMeta(x, y); mejorSeguro == -1 || dm < valorSeguro {
+				mejorSeguro, valorSeguro = int(d), dm
+			}
+		}
+	}
+
+	switch {
+	case !huir && mejorSeguro != -1:
+		a.dir, a.move = Direction(mejorSeguro), true
+	case mejorHuida != -1:
+		a.dir, a.move = Direction(mejorHuida), true
+	}
+}
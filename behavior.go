@@ -0,0 +1,145 @@
+package main
+
+// EnemyBehavior define la personalidad de un enemigo,
+// decide hacia que nodo del mapa se dirige en cada calculo de ruta
+type EnemyBehavior interface {
+	Name() string
+	Target(e *Enemy) *Node
+}
+
+// EnemyBehaviors crea los comportamientos por nombre, cada enemigo necesita
+// su propia instancia porque algunos comportamientos guardan estado
+var EnemyBehaviors = map[string]func(j *Game, spawn *Node) EnemyBehavior{
+	"persecucion": func(j *Game, spawn *Node) EnemyBehavior {
+		return &ChaseBehavior{}
+	},
+	"emboscada": func(j *Game, spawn *Node) EnemyBehavior {
+		return &AmbushBehavior{Cells: AmbushCells}
+	},
+	"patrulla": func(j *Game, spawn *Node) EnemyBehavior {
+		f, c := j.Maze.GetShape()
+		return &PatrolBehavior{
+			Waypoints: []*Node{
+				spawn.Clone(),
+				j.Maze.NearestWalkable(c/2, f/2),
+				j.Maze.NearestWalkable(c-1-spawn.X, spawn.Y),
+			},
+			Range: PatrolRange,
+		}
+	},
+	"deambular": func(j *Game, spawn *Node) EnemyBehavior {
+		return &WanderBehavior{}
+	},
+	"aprendido_q": func(j *Game, spawn *Node) EnemyBehavior {
+		return NewLearnedBehavior("aprendido_q", PolicyQFile)
+	},
+	"aprendido_dqn": func(j *Game, spawn *Node) EnemyBehavior {
+		return NewLearnedBehavior("aprendido_dqn", PolicyDQNFile)
+	},
+}
+
+// ChaseBehavior persigue directamente la posicion del jugador
+type ChaseBehavior struct{}
+
+func (b *ChaseBehavior) Name() string {
+	return "persecucion"
+}
+
+func (b *ChaseBehavior) Target(e *Enemy) *Node {
+	return e.TargetPlayer().NodePosition
+}
+
+// AmbushBehavior apunta varias celdas adelante del jugador,
+// segun la direccion en la que se esta moviendo, para cortarle el paso
+type AmbushBehavior struct {
+	Cells int // cuantas celdas adelante del jugador se embosca
+}
+
+func (b *AmbushBehavior) Name() string {
+	return "emboscada"
+}
+
+func (b *AmbushBehavior) Target(e *Enemy) *Node {
+	player := e.TargetPlayer()
+	maze := e.Maze()
+
+	dx, dy := player.CurrentDirection.Delta()
+	target := player.NodePosition.Clone()
+
+	// avanzamos celda por celda mientras sea transitable, asi nunca apuntamos a un muro
+	for i := 0; i < b.Cells; i++ {
+		next := NewNode(target.X+dx, target.Y+dy)
+		if !maze.IsWalkable(next.X, next.Y) {
+			break
+		}
+		target = next
+	}
+
+	// si ya estamos en el punto de emboscada o muy cerca del jugador, lo perseguimos directo
+	if target.Equal(e.NodePosition) || heuristica(e.NodePosition, player.NodePosition) <= 2 {
+		return player.NodePosition
+	}
+
+	return target
+}
+
+// PatrolBehavior recorre una lista de puntos de control,
+// cuando el jugador entra en su rango lo empieza a perseguir
+type PatrolBehavior struct {
+	Waypoints []*Node
+	Range     int // distancia (manhattan) a la que detecta al jugador
+	index     int
+}
+
+func (b *PatrolBehavior) Name() string {
+	return "patrulla"
+}
+
+func (b *PatrolBehavior) Target(e *Enemy) *Node {
+	if len(b.Waypoints) == 0 || b.InRange(e) {
+		return e.TargetPlayer().NodePosition
+	}
+
+	return b.LostTarget(e)
+}
+
+// InRange indica si el jugador esta dentro del rango de deteccion de la patrulla
+func (b *PatrolBehavior) InRange(e *Enemy) bool {
+	return heuristica(e.NodePosition, e.TargetPlayer().NodePosition) <= float64(b.Range)
+}
+
+// LostTarget sin ver al jugador sigue con su patrulla
+func (b *PatrolBehavior) LostTarget(e *Enemy) *Node {
+	if len(b.Waypoints) == 0 {
+		return e.NodePosition
+	}
+
+	// si ya llegamos al punto de control, pasamos al siguiente
+	if e.NodePosition.Equal(b.Waypoints[b.index]) {
+		b.index = (b.index + 1) % len(b.Waypoints)
+	}
+
+	return b.Waypoints[b.index]
+}
+
+// WanderBehavior deambula por el mapa eligiendo destinos aleatorios
+type WanderBehavior struct {
+	target *Node
+}
+
+func (b *WanderBehavior) Name() string {
+	return "deambular"
+}
+
+func (b *WanderBehavior) Target(e *Enemy) *Node {
+	// elegimos un nuevo destino si no tenemos uno o ya llegamos
+	if b.target == nil || e.NodePosition.Equal(b.target) {
+		b.target = e.Maze().RandomWalkable()
+	}
+	return b.target
+}
+
+// LostTarget deambular no depende de ver al jugador
+func (b *WanderBehavior) LostTarget(e *Enemy) *Node {
+	return b.Target(e)
+}
@@ -0,0 +1,37 @@
+package main
+
+// Hitbox es una caja de colision alineada a los ejes, en pixeles
+type Hitbox struct {
+	X, Y float64 // esquina superior izquierda
+	W, H float64
+}
+
+// NewHitbox crea la caja de colision de una celda dibujada en la posicion dada,
+// el margen de perdon la reduce por cada lado para que solo cuente un contacto real
+func NewHitbox(posicion *Vector2d, perdon float64) Hitbox {
+	lado := max(squareSize-2*perdon, 1)
+	return Hitbox{
+		X: posicion.X + (squareSize-lado)/2,
+		Y: posicion.Y + (squareSize-lado)/2,
+		W: lado,
+		H: lado,
+	}
+}
+
+// Intersects indica si dos cajas se traslapan
+func (h Hitbox) Intersects(other Hitbox) bool {
+	return h.X < other.X+other.W &&
+		other.X < h.X+h.W &&
+		h.Y < other.Y+other.H &&
+		other.Y < h.Y+h.H
+}
+
+// Hitbox del jugador segun su posicion interpolada
+func (player *Player) Hitbox(perdon float64) Hitbox {
+	return NewHitbox(player.CurrentPosition, perdon)
+}
+
+// Hitbox del enemigo segun su posicion interpolada
+func (e *Enemy) Hitbox(perdon float64) Hitbox {
+	return NewHitbox(e.VectorCurrentPosition, perdon)
+}
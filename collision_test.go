@@ -0,0 +1,33 @@
+package main
+
+import "testing"
+
+// con perdon p cada caja pierde p por lado, dos celdas chocan si estan a menos de squareSize - 2p
+func TestHitboxIntersects(t *testing.T) {
+	const p = CollisionForgiveness
+	casos := []struct {
+		nombre             string
+		separacion, perdon float64
+		choca              bool
+	}{
+		{"encimados", 0, p, true},
+		{"bordes_que_se_tocan", squareSize, 0, false},
+		{"traslape_minimo", squareSize - 0.5, 0, true},
+		{"en_el_limite_del_perdon", squareSize - 2*p, p, false},
+		{"dentro_del_limite_del_perdon", squareSize - 2*p - 0.5, p, true},
+		{"un_paso_de_perdon", squareSize - p, p, false},
+		// el perdon nunca deja la caja sin tamaño, dos en el mismo lugar siempre chocan
+		{"perdon_excesivo", 0, squareSize, true},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nombre, func(t *testing.T) {
+			a := NewHitbox(NewVector(0, 0), caso.perdon)
+			for _, pos := range []*Vector2d{NewVector(caso.separacion, 0), NewVector(0, caso.separacion)} {
+				b := NewHitbox(pos, caso.perdon)
+				if a.Intersects(b) != caso.choca || b.Intersects(a) != caso.choca {
+					t.Errorf("cajas %+v y %+v: choca %v, se esperaba %v", a, b, a.Intersects(b), caso.choca)
+				}
+			}
+		})
+	}
+}
@@ -27,7 +27,135 @@ const (
 
 	MaxAjolotePoints = NumAjolotes * AjolotePointValue
 
+	AmbushCells = 4 // celdas adelante del jugador donde se embosca un enemigo
+	PatrolRange = 8 // distancia a la que un enemigo en patrulla detecta al jugador
+
+	FrightenedEveryAjolotes = 10      // cada cuantos ajolotes recogidos se asustan los enemigos
+	FrightenedTicks         = 8 * TPS // duracion del modo asustado
+	FrightenedBlinkTicks    = 2 * TPS // ticks finales en los que el enemigo parpadea
+	FrightenedElapseFactor  = 2       // asustados tardan el doble en avanzar
+	EnemyEatenValue         = 200     // puntos por comer a un enemigo asustado
+
+	EnemySightRange = 12 // alcance de vision de los enemigos en celdas, 0 desactiva la vision
+	SearchRadius    = 10 // que tan lejos de la ultima posicion conocida busca un enemigo
+	SearchStep      = 2  // separacion entre los anillos del patron de busqueda
+
+	CooperativePlanning = true // los enemigos se coordinan para flanquear al jugador
+	EscapeDepth         = 8    // celdas maximas que se sigue un pasillo buscando una salida
+
+	CollisionForgiveness = 4.0 // margen de perdon en pixeles de las cajas de colision
+
+	EnemySpawnTicks   = 1 * TPS // duracion de la animacion de aparicion
+	SpawnSafeDistance = 6       // distancia minima en pasos entre el jugador y un punto de aparicion
+
+	DashElapse = 8  // lapso entre pasos mientras embiste
+	DashRange  = 10 // distancia maxima a la que embiste
+
+	EnemyAnimationElapse = TPS / 4 // ticks por frame de las animaciones de los enemigos
+	EnemyTurnSpeed       = 0.25    // cuanto cambia la escala horizontal por tick al darse la vuelta
+	EnemyTiltDegrees     = 20      // inclinacion al caminar hacia arriba o abajo
+	EnemyTiltSmoothing   = 0.2     // fraccion de la inclinacion que se alcanza por tick
+
 	DbName = "score.db"
 
+	SettingsFile    = "settings.json" // configuracion del jugador (controles)
+	DefaultDeadzone = 0.25            // zona muerta por defecto de las palancas
+	MenuMargin      = 40              // margen en pixeles del menu de pausa
+
+	// aprendizaje por refuerzo de los enemigos
+	PolicyQFile   = "politica_q"   // se guarda junto a modelo_velocidad.gob
+	PolicyDQNFile = "politica_dqn" // se guarda junto a modelo_velocidad.gob
+
+	SimFilas          = 21   // laberinto de las partidas simuladas
+	SimColumnas       = 21   //
+	SimMaxSteps       = 200  // pasos maximos de una partida simulada
+	SimSpawnDistance  = 10   // distancia minima inicial entre el enemigo y el jugador
+	SimPlayerSpeed    = 1.0  // celdas del jugador por cada celda del enemigo
+	SimPlayerNoise    = 0.1  // probabilidad de que el jugador scriptado haga un movimiento al azar
+	SimDangerRadius   = 4    // distancia a la que el jugador scriptado empieza a huir
+	SimCaptureReward  = 10.0 // recompensa por atrapar al jugador
+	SimStepReward     = -0.05
+	SimWallReward     = -0.2
+	SimApproachReward = 0.1 // recompensa por cada paso que se acerca al jugador
+
+	RLGamma        = 0.95
+	RLEpsilonMin   = 0.05
+	RLEpsilonDecay = 0.8 // fraccion de los episodios en los que epsilon baja hasta su minimo
+	RLEvalEpisodes = 200
+
+	QEpisodes = 5000
+	QAlpha    = 0.1
+
+	DQNEpisodes     = 1500
+	DQNHidden       = 32
+	DQNLearningRate = 0.01
+	DQNReplaySize   = 10000
+	DQNBatchSize    = 32
+	DQNTrainEvery   = 4   // pasos entre cada actualizacion de la red
+	DQNTargetSync   = 500 // pasos entre cada copia a la red objetivo
+
+	// power-ups, ocupan los valores de celda PowerUpFirstType a PowerUpFirstType+3
+	PowerUpFirstType       = 4
+	NumPowerUps            = 8 // power-ups que se colocan en el mapa
+	PowerUpSpeedTicks      = 6 * TPS
+	PowerUpFreezeTicks     = 4 * TPS
+	PowerUpInvisibleTicks  = 6 * TPS
+	PowerUpSpeedMultiplier = 1.5 // el jugador se mueve mas rapido mientras dura
+	PowerUpValue           = 100
+
+	// vidas
+	PlayerLives            = 3
+	InvulnerableTicks      = 2 * TPS // tiempo sin poder ser atrapado despues de reaparecer
+	InvulnerableBlinkTicks = 6       // ticks entre cada parpadeo mientras es invulnerable
+
+	// bot y partidas sin ventana
+	AutopilotSafeDistance = 4            // celdas que el bot intenta guardar con los perros
+	HeadlessGames         = 10           // partidas por defecto con --headless
+	HeadlessMaxTicks      = 5 * 60 * TPS // una partida sin ventana se corta a los 5 minutos
+
+	// dos jugadores
+	VersusDogArchetype = "perro" // tipo del perro que controla el segundo jugador
+	DogCatchValue      = 500     // puntos del perro del jugador por atrapar al otro
+
+	// red neuronal
+	LeakyReLUAlpha      = 0.01  // pendiente de leaky relu para los valores negativos
+	HuberDelta          = 1.0   // distancia a partir de la cual huber se vuelve lineal
+	CrossEntropyEpsilon = 1e-12 // evita log(0) en la entropia cruzada
+	MLPBatchSize        = 32    // muestras por actualizacion al entrenar el modelo de velocidad
+
+	// optimizadores y ajuste de la tasa de aprendizaje
+	MomentumBeta     = 0.9
+	RMSPropRho       = 0.9
+	AdamBeta1        = 0.9
+	AdamBeta2        = 0.999
+	OptimizerEpsilon = 1e-8 // evita dividir entre cero
+	LRStepCount      = 4    // veces que baja la tasa con el ajuste en escalon
+	LRStepFactor     = 0.5  // cuanto se multiplica la tasa en cada escalon
+	LRMinFactor      = 0.01 // fraccion de la tasa original al terminar con exponencial y coseno
+
+	// archivos de modelos
+	VelocityModelFile  = "modelo_velocidad" // sin la extension .gob
+	ModelFormatName    = "mazegame-mlp"
+	ModelFormatVersion = 2 // la 1 son los archivos sin encabezado, solo con pesos y bias
+	RidgeModelFile     = "modelo_velocidad_ridge"
+	SpeedModelMLP      = "mlp"   // modelos que se pueden elegir para predecir la velocidad
+	SpeedModelRidge    = "ridge" //
+	SpeedHistoryGames  = 10      // partidas previas que reciben los predictores de velocidad
+	SpeedAverageWindow = 5       // partidas que promedia el predictor media
+	RidgeFormatName    = "mazegame-ridge"
+	RidgeFormatVersion = 1
+
+	// validacion del modelo de velocidad
+	ValidationFraction    = 0.15 // registros para decidir cuando detener el entrenamiento
+	TestFraction          = 0.15 // registros que solo se usan para medir el modelo
+	EarlyStoppingPatience = 500  // epocas sin mejorar en validacion antes de detenerse
+	MinTrainingSamples    = 4    // registros minimos en la particion de entrenamiento de la red
+
+	// telemetria y seleccion de caracteristicas
+	NearMissCells    = 3    // pasos a los que un perro cuenta como casi atraparlo
+	SelectionFolds   = 5    // pliegues para comparar conjuntos de caracteristicas
+	SelectionMinGain = 0.01 // mejora relativa del RMSE para agregar otra caracteristica
+	SelectionAuto    = "auto"
+
 	sampleRate = 44100
 )
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"math"
+	"slices"
+)
+
+// Coordinator reparte a los enemigos que persiguen al jugador entre sus rutas de escape,
+// asi en lugar de formarse uno detras de otro lo rodean y lo atrapan en los cruces
+type Coordinator struct {
+	Assignments map[*Enemy]*Node // salida que tiene que cubrir cada enemigo que flanquea
+}
+
+func NewCoordinator() *Coordinator {
+	return &Coordinator{
+		Assignments: map[*Enemy]*Node{},
+	}
+}
+
+// Plan recalcula las asignaciones, cada jugador se planea por separado con los enemigos que lo persiguen
+func (c *Coordinator) Plan(j *Game) {
+	clear(c.Assignments)
+
+	grupos := map[*Player][]*Enemy{}
+	for _, e := range j.Enemys {
+		if !e.IsSpawning() && e.Mode() == ChaseMode && e.IsPursuer() && e.CanSeePlayer() {
+			p := e.TargetPlayer()
+			grupos[p] = append(grupos[p], e)
+		}
+	}
+
+	for p, perseguidores := range grupos {
+		c.planPlayer(j.Maze, p.NodePosition, perseguidores)
+	}
+}
+
+// planPlayer el perseguidor mas cercano va directo al jugador
+// y el resto se reparte entre las salidas que le quedan libres
+func (c *Coordinator) planPlayer(maze Maze, jugador *Node, perseguidores []*Enemy) {
+	if len(perseguidores) < 2 {
+		return // un solo perseguidor no tiene con quien coordinarse
+	}
+
+	distJugador := maze.DistanceField(jugador)
+
+	distancia := func(campo [][]int, n *Node) int {
+		if d := campo[n.Y][n.X]; d >= 0 {
+			return d
+		}
+		return math.MaxInt32 // inalcanzable
+	}
+
+	// ordenamos por distancia al jugador, el primero lo persigue directo
+	slices.SortFunc(perseguidores, func(a, b *Enemy) int {
+		return distancia(distJugador, a.NodePosition) - distancia(distJugador, b.NodePosition)
+	})
+	directo := perseguidores[0]
+	flancos := perseguidores[1:]
+
+	salidas := maze.EscapeRoutes(jugador, EscapeDepth)
+	if len(salidas) == 0 {
+		return
+	}
+
+	// descartamos la salida por la que ya llega el perseguidor directo
+	distDirecto := maze.DistanceField(directo.NodePosition)
+	cubierta := 0
+	for i, s := range salidas {
+		if distancia(distDirecto, s) < distancia(distDirecto, salidas[cubierta]) {
+			cubierta = i
+		}
+	}
+	salidas = slices.Delete(salidas, cubierta, cubierta+1)
+
+	campos := make([][][]int, len(flancos))
+	for i, e := range flancos {
+		campos[i] = maze.DistanceField(e.NodePosition)
+	}
+
+	// asignacion voraz: repetimos tomando el par (enemigo, salida) mas cercano
+	asignado := make([]bool, len(flancos))
+	for len(salidas) > 0 {
+		mejorEnemigo, mejorSalida := -1, -1
+		mejorDistancia := math.MaxInt32
+		for i, e := range flancos {
+			if asignado[i] {
+				continue
+			}
+			for k, s := range salidas {
+				// si ya esta mas cerca del jugador que la salida, ya la paso, lo dejamos perseguir
+				if distancia(distJugador, e.NodePosition) <= distancia(distJugador, s) {
+					continue
+				}
+				if d := distancia(campos[i], s); d < mejorDistancia {
+					mejorEnemigo, mejorSalida, mejorDistancia = i, k, d
+				}
+			}
+		}
+
+		if mejorEnemigo == -1 {
+			break
+		}
+
+		asignado[mejorEnemigo] = true
+		c.Assignments[flancos[mejorEnemigo]] = salidas[mejorSalida]
+		salidas = slices.Delete(salidas, mejorSalida, mejorSalida+1)
+	}
+}
+
+// IsPursuer indica si el comportamiento del enemigo lo lleva a perseguir al jugador,
+// los aprendidos no se coordinan, su politica decide sola para poder compararla con A*
+func (e *Enemy) IsPursuer() bool {
+	switch b := e.Behavior.(type) {
+	case *ChaseBehavior, *AmbushBehavior:
+		return true
+	case *PatrolBehavior:
+		return b.InRange(e)
+	}
+	return false
+}
+
+// EscapeRoutes sigue cada pasillo que sale del origen hasta el siguiente cruce,
+// los cruces alcanzados son las salidas por las que puede escapar el jugador.
+// Los callejones sin salida no cuentan como ruta de escape
+func (m Maze) EscapeRoutes(origen *Node, profundidad int) []*Node {
+	dirs := []Pos{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
+	var salidas []*Node
+
+	for _, d := range dirs {
+		anterior := origen
+		actual := NewNode(origen.X+d.X, origen.Y+d.Y)
+		if !m.IsWalkable(actual.X, actual.Y) {
+			continue
+		}
+
+		callejon := false
+		for paso := 1; paso < profundidad; paso++ {
+			if m.OpenNeighbors(actual.X, actual.Y) >= 3 {
+				break // llegamos a un cruce
+			}
+
+			var siguiente *Node
+			for _, dd := range dirs {
+				n := NewNode(actual.X+dd.X, actual.Y+dd.Y)
+				if m.IsWalkable(n.X, n.Y) && !n.Equal(anterior) {
+					siguiente = n
+					break
+				}
+			}
+
+			if siguiente == nil {
+				callejon = true
+				break
+			}
+			anterior, actual = actual, siguiente
+		}
+
+		if !callejon {
+			salidas = append(salidas, actual)
+		}
+	}
+
+	return salidas
+}
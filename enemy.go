@@ -2,36 +2,109 @@ package main
 
 import (
 	"log"
+	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
+// EnemyMoves son los movimientos de una celda que puede hacer un enemigo, los mismos que usa A*
+var EnemyMoves = []Node{
+	{X: 1, Y: -1},
+	{X: -1, Y: -1},
+	{X: -1, Y: 0},
+	{X: 1, Y: 0},
+	{X: 0, Y: 1},
+	{X: 1, Y: 1},
+	{X: -1, Y: 1},
+	{X: 0, Y: -1},
+}
+
 type Enemy struct {
-	Animation             *Animation // animacion de los sprites
-	NodePosition          *Node      // indica la posicion dentro del mapa
-	VectorCurrentPosition *Vector2d  // los vectores son utilizados para calcular un desplazamiento suave
+	StayAnimation         *Animation               // animacion quieto
+	MovingAnimation       *Animation               // animacion caminando, se voltea segun la direccion
+	DirectionAnimations   map[Direction]*Animation // animaciones caminando propias de una direccion
+	NodePosition          *Node                    // indica la posicion dentro del mapa
+	VectorCurrentPosition *Vector2d                // los vectores son utilizados para calcular un desplazamiento suave
 	VectorTargetPosition  *Vector2d
 	Elapse                int // lapso de tiempo en que se realiza el calculo del posicion del jugador
 	ElapseDecrement       int // decrementos en avance en la reducion de lapso
+	InitialElapse         int // lapso con el que empezo la partida
+	MinElapse             int // lapso minimo, el enemigo no se vuelve mas rapido que esto
 	TickCounter           int
 	Juego                 *Game
 	PathIndex             int
 	Path                  []*Node
 	IsMoving              bool
+	Archetype             *EnemyArchetype // tipo de enemigo
+	Behavior              EnemyBehavior   // personalidad del enemigo, decide hacia donde se dirige
+	Ability               EnemyAbility    // habilidad especial del tipo
+	Spawn                 *Node           // nodo donde aparece y reaparece al ser comido
+	HomeCorner            *Node           // esquina a la que se dispersa en modo dispersion
+	Eaten                 bool            // indica si ya fue comido durante el modo asustado actual
+	SightRange            int             // alcance de vision en celdas, 0 indica que siempre ve al jugador
+	LastKnown             *Node           // ultima posicion donde vio al jugador
+	SearchTargets         []*Node         // puntos pendientes del patron de busqueda
+	Searching             bool            // indica si ya llego a la ultima posicion conocida y esta buscando
+	wander                WanderBehavior
+	SpawnTicks            int         // ticks restantes de la animacion de aparicion
+	Facing                Direction   // direccion a la que mira
+	FacingScaleX          float64     // escala horizontal del sprite, va de 1 a -1 al darse la vuelta
+	FacingAngle           float64     // inclinacion del sprite al caminar hacia arriba o abajo
+	Input                 InputSource // en versus, controles del jugador que maneja al perro
+	Points                uint        // en versus, puntaje del jugador que maneja al perro
+	target                *Player     // jugador al que persigue en este tick
+	targetTick            int
 }
 
 type Enemys []*Enemy
 
-// CalculatePath actualiza el path hacia el jugador
+// EnemySpeed describe la curva de velocidad de un enemigo
+type EnemySpeed struct {
+	Elapse          int // lapso inicial
+	ElapseDecrement int // cuanto se reduce el lapso por cada ajolote recogido
+	MinElapse       int // piso del lapso
+}
+
+// NewEnemySpeed crea una curva de velocidad, el lapso inicial se acota entre el piso y EnemyElapseMax
+func NewEnemySpeed(elapse, decrement, minElapse int) EnemySpeed {
+	minElapse = max(minElapse, EnemyElapseMin)
+	return EnemySpeed{
+		Elapse:          max(minElapse, min(elapse, EnemyElapseMax)),
+		ElapseDecrement: decrement,
+		MinElapse:       minElapse,
+	}
+}
+
+// SpeedUp reduce el lapso del enemigo sin bajar de su piso
+func (e *Enemy) SpeedUp() {
+	e.Elapse = max(e.Elapse-e.ElapseDecrement, e.MinElapse)
+}
+
+// CalculatePath actualiza el path hacia el objetivo que indique su comportamiento
 func (e *Enemy) CalculatePath() {
 	// tenemos que liberar todos las referencias de memoria en camino previo
 	for _, c := range e.Path {
 		c.parent = nil
 	}
 
-	maze := e.Juego.Maze
-	meta := e.Juego.Player.NodePosition
+	maze := e.Maze()
+
+	// el modo global decide el objetivo, solo en persecucion se usa la personalidad
+	var meta *Node
+	switch e.Mode() {
+	case ScatterMode:
+		meta = e.HomeCorner
+	case FrightenedMode:
+		meta = e.FleeTarget()
+	default:
+		meta = e.ChaseTarget()
+	}
+
 	nodoMeta := AStart(maze, e.NodePosition, meta)
+	// si su habilidad le cierra el paso (ajolotes que no pisa), se acerca lo mas que puede
+	if nodoMeta == nil {
+		nodoMeta = AStart(maze, e.NodePosition, maze.NearestReachable(e.NodePosition, meta))
+	}
 
 	if nodoMeta == nil {
 		log.Fatalln("Meta no calulada")
@@ -39,10 +112,191 @@ func (e *Enemy) CalculatePath() {
 	e.Path = nodoMeta.BuildWay()
 }
 
+// Mode regresa el modo en el que se encuentra el enemigo,
+// un enemigo que ya fue comido deja de estar asustado
+func (e *Enemy) Mode() EnemyMode {
+	modo := e.Juego.Modes.Mode()
+	if modo == FrightenedMode && e.Eaten {
+		return e.Juego.Modes.BaseMode()
+	}
+	return modo
+}
+
+// CurrentElapse regresa el lapso entre pasos, asustado se mueve mas lento
+func (e *Enemy) CurrentElapse() int {
+	if e.Mode() == FrightenedMode {
+		return e.Elapse * FrightenedElapseFactor
+	}
+	return e.Ability.ModifyElapse(e, e.Elapse)
+}
+
+// Maze el laberinto como lo ve el enemigo, las celdas que bloquea su habilidad son muros
+// menos en la que esta parado, asi siempre puede salir de ella
+func (e *Enemy) Maze() Maze {
+	maze := e.Juego.Maze
+	var vista Maze
+	for y, fila := range maze {
+		for x, celda := range fila {
+			if !e.Ability.Blocks(celda) || (x == e.NodePosition.X && y == e.NodePosition.Y) {
+				continue
+			}
+			if vista == nil {
+				vista = maze.Clone()
+			}
+			vista.Set(x, y, 1)
+		}
+	}
+	if vista == nil {
+		return maze
+	}
+	return vista
+}
+
+// FleeTarget elige el nodo vecino que mas lo aleja del jugador
+func (e *Enemy) FleeTarget() *Node {
+	maze := e.Maze()
+	jugador := e.TargetPlayer().NodePosition
+
+	mejor := e.NodePosition
+	mejorDistancia := heuristica(e.NodePosition, jugador)
+
+	for _, mov := range EnemyMoves {
+		vecino := NewNode(e.NodePosition.X+mov.X, e.NodePosition.Y+mov.Y)
+		if !maze.IsWalkable(vecino.X, vecino.Y) {
+			continue
+		}
+		if d := heuristica(vecino, jugador); d > mejorDistancia {
+			mejor = vecino
+			mejorDistancia = d
+		}
+	}
+
+	return mejor
+}
+
+// Respawn regresa al enemigo a su punto de aparicion
+func (e *Enemy) Respawn() {
+	for _, c := range e.Path {
+		c.parent = nil
+	}
+	e.Path = nil
+	e.NodePosition = e.Spawn.Clone()
+	e.VectorCurrentPosition = NewVector(
+		float64(e.NodePosition.X*squareSize),
+		float64(e.NodePosition.Y*squareSize),
+	)
+	e.UpdateVectorTargetPosition()
+	e.IsMoving = false
+	e.TickCounter = 0
+	e.LastKnown = nil
+	e.SearchTargets = nil
+	e.Searching = false
+	e.SpawnTicks = EnemySpawnTicks
+}
+
+// CurrentAnimation regresa la animacion segun si camina y hacia donde
+func (e *Enemy) CurrentAnimation() *Animation {
+	if !e.IsMoving {
+		return e.StayAnimation
+	}
+	if a, ok := e.DirectionAnimations[e.Facing]; ok {
+		return a
+	}
+	return e.MovingAnimation
+}
+
+// UpdateFacing calcula hacia donde mira segun hacia donde se desplaza,
+// el giro y la inclinacion se suavizan en varios ticks
+func (e *Enemy) UpdateFacing() {
+	if e.IsMoving {
+		dir := e.VectorTargetPosition.Sub(e.VectorCurrentPosition)
+		// tomamos el eje dominante, en diagonal preferimos el horizontal
+		if math.Abs(dir.X) >= math.Abs(dir.Y) && dir.X != 0 {
+			if dir.X > 0 {
+				e.Facing = DirectionRight
+			} else {
+				e.Facing = DirectionLeft
+			}
+		} else if dir.Y > 0 {
+			e.Facing = DirectionDown
+		} else if dir.Y < 0 {
+			e.Facing = DirectionUp
+		}
+	}
+
+	// hacia arriba o abajo conserva el ultimo lado al que miraba
+	escala := 1.0
+	if e.FacingScaleX < 0 {
+		escala = -1.0
+	}
+	switch e.Facing {
+	case DirectionRight:
+		escala = 1
+	case DirectionLeft:
+		escala = -1
+	}
+
+	// los frames miran a la derecha salvo que el tipo indique lo contrario
+	if e.Archetype.FacesLeft {
+		escala = -escala
+	}
+
+	// la escala avanza poco a poco para que se vea como se da la vuelta
+	if e.FacingScaleX < escala {
+		e.FacingScaleX = min(e.FacingScaleX+EnemyTurnSpeed, escala)
+	} else if e.FacingScaleX > escala {
+		e.FacingScaleX = max(e.FacingScaleX-EnemyTurnSpeed, escala)
+	}
+
+	// levantamos o bajamos la nariz, el signo depende del lado al que mira
+	inclinacion := 0.0
+	switch e.Facing {
+	case DirectionUp:
+		inclinacion = -gradosARadianes(EnemyTiltDegrees) * escala
+	case DirectionDown:
+		inclinacion = gradosARadianes(EnemyTiltDegrees) * escala
+	}
+	e.FacingAngle += (inclinacion - e.FacingAngle) * EnemyTiltSmoothing
+}
+
 func (e *Enemy) Draw(screen *ebiten.Image) {
 	imgOptions := &ebiten.DrawImageOptions{}
+	frame := e.CurrentAnimation().GetFrame()
+	bounds := frame.Bounds()
+	w := float64(bounds.Dx())
+	h := float64(bounds.Dy())
+
+	// las transformaciones se hacen desde el centro del sprite
+	imgOptions.GeoM.Translate(-w/2, -h/2)
+
+	// con una animacion propia de la direccion no hace falta voltear ni inclinar
+	if _, ok := e.DirectionAnimations[e.Facing]; !ok || !e.IsMoving {
+		imgOptions.GeoM.Scale(e.FacingScaleX, 1)
+		imgOptions.GeoM.Rotate(e.FacingAngle)
+	}
+
+	// al aparecer crece desde el centro de la celda y se va haciendo visible
+	if e.IsSpawning() {
+		progreso := 1 - float64(e.SpawnTicks)/float64(EnemySpawnTicks)
+		imgOptions.GeoM.Scale(progreso, progreso)
+		imgOptions.ColorScale.ScaleAlpha(float32(progreso))
+	}
+
+	imgOptions.GeoM.Translate(w/2, h/2)
+
 	imgOptions.GeoM.Translate(e.VectorCurrentPosition.X, e.VectorCurrentPosition.Y)
-	frame := e.Animation.GetFrame()
+
+	// cada tipo se distingue por su color
+	tinte := e.Archetype.Tint
+	imgOptions.ColorScale.Scale(tinte[0], tinte[1], tinte[2], 1)
+
+	// asustado se pinta de azul, cuando esta por terminar parpadea en blanco
+	if e.Mode() == FrightenedMode {
+		restantes := e.Juego.Modes.FrightenedTicks
+		if restantes > FrightenedBlinkTicks || (restantes/10)%2 == 0 {
+			imgOptions.ColorScale.Scale(0.3, 0.3, 1, 1)
+		}
+	}
 
 	screen.DrawImage(frame, imgOptions)
 }
@@ -58,24 +312,49 @@ func (e *Enemy) UpdateVectorTargetPosition() {
 	)
 }
 
+// IsSpawning indica si el enemigo sigue apareciendo, mientras no se mueve ni colisiona
+func (e *Enemy) IsSpawning() bool {
+	return e.SpawnTicks > 0
+}
+
 // Tick determina los avances en lapsos de avance
 func (e *Enemy) Tick() {
-	e.Animation.Tick()
+	e.CurrentAnimation().Tick()
+	e.UpdateFacing()
 
-	if !e.IsMoving {
+	if e.IsSpawning() {
+		e.SpawnTicks--
+		return
+	}
+
+	// congelados terminan el paso que llevaban pero no avanzan su contador
+	congelado := e.Juego.Effects.Active(PowerUpFreeze)
+	if !congelado {
+		e.Ability.Tick(e)
+	}
+
+	if !e.IsMoving && !congelado {
 		// si no esta movmiento, calcualmos el siguiente paso
 		e.TickCounter++ // este tick es para avanzar el calculo de la ia
-		if e.TickCounter > e.Elapse {
+		if e.Input != nil {
+			// el perro de un jugador espera su lapso y despues avanza en cuanto se lo piden
+			if e.TickCounter > e.CurrentElapse() && e.Steer() {
+				e.TickCounter = 0
+			}
+		} else if e.TickCounter > e.CurrentElapse() {
 			// si se pasa, avanzamos un cuadrando al camino
 			e.TickCounter = 0
-			e.IsMoving = true
 			// calculamos a cada paso la ruta al enemigo
 			e.CalculatePath()
-			// dado el path se actualiza cada elapse,
-			// siempre se avanza al segundo elemento de la ruta
-			e.NodePosition = e.Path[e.PathIndex]
+			// si ya esta en su objetivo la ruta solo contiene su posicion, se queda quieto
+			if len(e.Path) > e.PathIndex {
+				e.IsMoving = true
+				// dado el path se actualiza cada elapse,
+				// siempre se avanza al segundo elemento de la ruta
+				e.NodePosition = e.Path[e.PathIndex]
 
-			e.UpdateVectorTargetPosition()
+				e.UpdateVectorTargetPosition()
+			}
 		}
 	}
 
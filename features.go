@@ -0,0 +1,253 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Caracteristica columna que puede recibir el modelo de velocidad
+type Caracteristica struct {
+	Nombre string
+	// Valor false si la partida no la registro
+	Valor func(s GameScore) (float64, bool)
+}
+
+// deTelemetria las partidas de antes de la telemetria no tienen estas columnas
+func deTelemetria(f func(t *MatchTelemetry) float64) func(GameScore) (float64, bool) {
+	return func(s GameScore) (float64, bool) {
+		if s.Telemetry == nil {
+			return 0, false
+		}
+		return f(s.Telemetry), true
+	}
+}
+
+// porMinuto divide entre el tiempo que jugo, asi no pesa lo que duro la partida
+func porMinuto(n float64, t *MatchTelemetry) float64 {
+	if t.PlayedSeconds == 0 {
+		return 0
+	}
+	return n / (t.PlayedSeconds / 60)
+}
+
+// CandidateFeatures todo lo que se registra de una partida y puede usar el modelo
+var CandidateFeatures = []Caracteristica{
+	{"score", func(s GameScore) (float64, bool) { return float64(s.Score), true }},
+	{"time", func(s GameScore) (float64, bool) { return s.Time, true }},
+	{"lives_used", func(s GameScore) (float64, bool) { return float64(s.LivesUsed), true }},
+	{"ajolotes_per_minute", deTelemetria(func(t *MatchTelemetry) float64 { return t.AjolotesPerMinute })},
+	{"near_misses_per_minute", deTelemetria(func(t *MatchTelemetry) float64 { return porMinuto(float64(t.NearMisses), t) })},
+	{"avg_dog_distance", deTelemetria(func(t *MatchTelemetry) float64 { return t.AvgDogDistance })},
+	{"direction_changes_per_minute", deTelemetria(func(t *MatchTelemetry) float64 { return porMinuto(float64(t.DirectionChanges), t) })},
+	{"idle_fraction", deTelemetria(func(t *MatchTelemetry) float64 {
+		if t.PlayedSeconds == 0 {
+			return 0
+		}
+		return t.IdleSeconds / t.PlayedSeconds
+	})},
+	{"maze_dead_ends", deTelemetria(func(t *MatchTelemetry) float64 { return float64(t.Maze.DeadEnds) })},
+	{"maze_junctions", deTelemetria(func(t *MatchTelemetry) float64 { return float64(t.Maze.Junctions) })},
+	{"maze_farthest_cell", deTelemetria(func(t *MatchTelemetry) float64 { return float64(t.Maze.FarthestCell) })},
+}
+
+// NombresCaracteristicas nombres de todas las candidatas, en orden
+func NombresCaracteristicas() []string {
+	nombres := make([]string, len(CandidateFeatures))
+	for i, c := range CandidateFeatures {
+		nombres[i] = c.Nombre
+	}
+	return nombres
+}
+
+func CaracteristicaPorNombre(nombre string) (Caracteristica, error) {
+	for _, c := range CandidateFeatures {
+		if c.Nombre == nombre {
+			return c, nil
+		}
+	}
+	return Caracteristica{}, fmt.Errorf("caracteristica desconocida: %s", nombre)
+}
+
+// validarCaracteristicas revisa que el juego sepa calcular cada columna que guardo un modelo
+func validarCaracteristicas(nombres []string) error {
+	for _, nombre := range nombres {
+		if _, err := CaracteristicaPorNombre(nombre); err != nil {
+			return fmt.Errorf("el modelo usa una columna que esta version no calcula: %v", err)
+		}
+	}
+	return nil
+}
+
+// FilaCaracteristicas fila de entrada del modelo para una partida con las columnas dadas
+func FilaCaracteristicas(s GameScore, nombres []string) ([]float64, error) {
+	fila := make([]float64, len(nombres))
+	for j, nombre := range nombres {
+		c, err := CaracteristicaPorNombre(nombre)
+		if err != nil {
+			return nil, err
+		}
+		v, ok := c.Valor(s)
+		if !ok {
+			return nil, fmt.Errorf("la partida no registro %s", nombre)
+		}
+		fila[j] = v
+	}
+	return fila, nil
+}
+
+/*
+MatrizCaracteristicas arma X con las columnas dadas y Yd con la velocidad,
+las partidas a las que les falta alguna columna se saltan
+:return: X, Yd y cuantas partidas se saltaron
+*/
+func MatrizCaracteristicas(scores []GameScore, nombres []string) (*mat.Dense, *mat.Dense, int) {
+	var dataX, dataY []float64
+	saltadas := 0
+	for _, s := range scores {
+		fila, err := FilaCaracteristicas(s, nombres)
+		if err != nil {
+			saltadas++
+			continue
+		}
+		dataX = append(dataX, fila...)
+		// la salida es lineal, asi que el modelo predice el lapso directamente
+		dataY = append(dataY, float64(s.Velocity))
+	}
+	n := len(dataY)
+	if n == 0 {
+		return nil, nil, saltadas
+	}
+	return mat.NewDense(n, len(nombres), dataX), mat.NewDense(n, 1, dataY), saltadas
+}
+
+/*
+DividirPartidas separa las partidas igual que DividirDatos pero antes de armar X,
+asi la seleccion de caracteristicas solo ve las de entrenamiento y la prueba no se filtra
+:return: partidas de entrenamiento, validacion y prueba
+*/
+func DividirPartidas(scores []GameScore, validacion, prueba float64) ([]GameScore, []GameScore, []GameScore) {
+	entrenamiento, val, test := ordenParticiones(len(scores), validacion, prueba)
+	return tomarPartidas(scores, entrenamiento), tomarPartidas(scores, val), tomarPartidas(scores, test)
+}
+
+func tomarPartidas(scores []GameScore, indices []int) []GameScore {
+	partidas := make([]GameScore, len(indices))
+	for k, i := range indices {
+		partidas[k] = scores[i]
+	}
+	return partidas
+}
+
+// ParticionCaracteristicas particion con las columnas dadas, vacia si ninguna partida las tiene todas
+func ParticionCaracteristicas(scores []GameScore, nombres []string) (Particion, int) {
+	X, Yd, saltadas := MatrizCaracteristicas(scores, nombres)
+	return Particion{X: X, Y: Yd}, saltadas
+}
+
+// tomarColumnas particion solo con las columnas indicadas de X
+func tomarColumnas(p Particion, columnas []int) Particion {
+	filas := p.Filas()
+	X := mat.NewDense(filas, len(columnas), nil)
+	for i := 0; i < filas; i++ {
+		for k, j := range columnas {
+			X.Set(i, k, p.X.At(i, j))
+		}
+	}
+	return Particion{X: X, Y: p.Y}
+}
+
+// rmseCruzado RMSE promedio de ridge en los pliegues usando solo algunas columnas,
+// sin columnas predice la media del entrenamiento
+func rmseCruzado(pliegues []Pliegue, columnas []int) (float64, error) {
+	suma := 0.0
+	for _, p := range pliegues {
+		deseados := mat.Col(nil, 0, p.Prueba.Y)
+		predichos := make([]float64, len(deseados))
+		if len(columnas) == 0 {
+			media := Vector(mat.Col(nil, 0, p.Entrenamiento.Y)).Media()
+			for i := range predichos {
+				predichos[i] = media
+			}
+		} else {
+			modelo, err := ajustarRidge(tomarColumnas(p.Entrenamiento, columnas), DefaultRidgeConfig(), nil)
+			if err != nil {
+				return 0, err
+			}
+			predichos = modelo.Lapsos(tomarColumnas(p.Prueba, columnas).X)
+		}
+		suma += CalcularMetricas(deseados, predichos).RMSE
+	}
+	return suma / float64(len(pliegues)), nil
+}
+
+/*
+SeleccionarCaracteristicas seleccion hacia adelante: empieza sin columnas y en cada paso
+agrega la candidata que mas baja el RMSE de la validacion cruzada, se detiene cuando
+ninguna lo mejora al menos SelectionMinGain.
+Se mide con ridge en forma cerrada porque es rapido, con pocas partidas entrenar una red
+por cada combinacion tarda mucho y lo que importa es que columnas traen informacion.
+Solo se usan las partidas que tienen todas las candidatas, deben ser solo las de entrenamiento
+para que la validacion y la prueba no ayuden a elegir las columnas
+*/
+func SeleccionarCaracteristicas(scores []GameScore, candidatas []string, k int) ([]string, error) {
+	X, Yd, _ := MatrizCaracteristicas(scores, candidatas)
+	if X == nil {
+		return nil, fmt.Errorf("ninguna partida tiene todas las caracteristicas candidatas")
+	}
+	n, _ := X.Dims()
+	if n < 2*k {
+		return nil, fmt.Errorf("solo %d partidas tienen todas las caracteristicas, se necesitan al menos %d", n, 2*k)
+	}
+	fmt.Printf("Seleccion de caracteristicas con %d partidas y %d pliegues...\n", n, k)
+
+	// las columnas constantes no aportan nada
+	var restantes []int
+	for j, nombre := range candidatas {
+		if Vector(mat.Col(nil, j, X)).StandardDeviation() == 0 {
+			fmt.Printf("  %s no cambia entre partidas, se descarta\n", nombre)
+			continue
+		}
+		restantes = append(restantes, j)
+	}
+
+	// los mismos pliegues para todas las combinaciones, asi se comparan con los mismos datos
+	pliegues := Pliegues(X, Yd, k)
+	var elegidas []int
+	mejor, err := rmseCruzado(pliegues, nil)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("  sin caracteristicas (la media): RMSE %.3f\n", mejor)
+
+	for len(restantes) > 0 {
+		paso, rmsePaso := -1, math.Inf(1)
+		for _, j := range restantes {
+			rmse, err := rmseCruzado(pliegues, append(slices.Clone(elegidas), j))
+			if err != nil {
+				continue // depende de las que ya se eligieron, no agrega nada
+			}
+			if rmse < rmsePaso {
+				paso, rmsePaso = j, rmse
+			}
+		}
+		if rmsePaso >= mejor*(1-SelectionMinGain) {
+			break
+		}
+		fmt.Printf("  + %s: RMSE %.3f\n", candidatas[paso], rmsePaso)
+		elegidas = append(elegidas, paso)
+		restantes = slices.DeleteFunc(restantes, func(j int) bool { return j == paso })
+		mejor = rmsePaso
+	}
+
+	if len(elegidas) == 0 {
+		return nil, fmt.Errorf("ninguna caracteristica mejora a predecir la media")
+	}
+	nombres := make([]string, len(elegidas))
+	for i, j := range elegidas {
+		nombres[i] = candidatas[j]
+	}
+	return nombres, nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// RunHeadless juega partidas con el bot sin abrir ventana y las guarda en la base de datos,
+// cada partida predice su velocidad con la anterior igual que al jugar
+func RunHeadless(db *gorm.DB, settings *Settings, noPredict bool, partidas int, modo PlayMode) {
+	if modo == VersusMode {
+		log.Fatal("el modo versus necesita un segundo jugador, no se puede jugar sin ventana")
+	}
+
+	for i := 0; i < partidas; i++ {
+		juego, err := NewGame(db, settings, PredictElapse(db, settings, noPredict), true, modo)
+		if err != nil {
+			log.Fatal(err)
+		}
+		juego.Bot = true
+		for _, p := range juego.Players {
+			p.Input = NewAutopilot(juego, p, AutopilotSafeDistance)
+		}
+
+		for juego.State != GameOverState {
+			if err := juego.Update(); err != nil {
+				log.Fatal(err)
+			}
+			// el bot se puede quedar dando vueltas, cortamos la partida
+			if juego.State == PlayingState && juego.Ticks >= HeadlessMaxTicks {
+				juego.Truncada = true
+				juego.GameOver()
+			}
+		}
+
+		for _, p := range juego.Players {
+			fmt.Printf("Partida %d, jugador %d: puntaje %d, tiempo %.1fs, ajolotes %d/%d, vidas usadas %d",
+				i+1, p.Index+1, p.Points, juego.ElapsedSeconds(), p.Ajolotes, NumAjolotes, p.LivesUsed)
+			if juego.Truncada {
+				fmt.Print(" (cortada)")
+			}
+			fmt.Println()
+		}
+	}
+	fmt.Println("Las partidas del bot no se usan al entrenar, para incluirlas usa train --bot=true")
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Action es una accion del juego a la que se le asignan teclas y botones
+type Action int
+
+const (
+	ActionUp Action = iota
+	ActionDown
+	ActionLeft
+	ActionRight
+	ActionPause
+	ActionConfirm
+)
+
+// Actions todas las acciones en el orden en que se muestran en el menu
+var Actions = []Action{ActionUp, ActionDown, ActionLeft, ActionRight, ActionPause, ActionConfirm}
+
+var actionNames = map[Action]string{
+	ActionUp:      "arriba",
+	ActionDown:    "abajo",
+	ActionLeft:    "izquierda",
+	ActionRight:   "derecha",
+	ActionPause:   "pausa",
+	ActionConfirm: "aceptar",
+}
+
+func (a Action) String() string {
+	return actionNames[a]
+}
+
+// MarshalText permite usar la accion como llave en el archivo de configuracion
+func (a Action) MarshalText() ([]byte, error) {
+	nombre, ok := actionNames[a]
+	if !ok {
+		return nil, fmt.Errorf("accion desconocida: %d", a)
+	}
+	return []byte(nombre), nil
+}
+
+func (a *Action) UnmarshalText(text []byte) error {
+	for accion, nombre := range actionNames {
+		if nombre == string(text) {
+			*a = accion
+			return nil
+		}
+	}
+	return fmt.Errorf("accion desconocida: %s", text)
+}
+
+// InputSource es de donde el jugador recibe sus acciones: teclado, control o un bot
+type InputSource interface {
+	Pressed(a Action) bool
+	JustPressed(a Action) bool
+}
+
+// AxisBinding asigna un lado de un eje analogico a una accion
+type AxisBinding struct {
+	Axis ebiten.StandardGamepadAxis `json:"axis"`
+	Sign float64                    `json:"sign"` // -1 o 1, lado del eje que activa la accion
+}
+
+// Binding teclas, botones (distribucion estandar de ebiten) y ejes asignados a una accion
+type Binding struct {
+	Keys    []ebiten.Key                   `json:"keys"`
+	Buttons []ebiten.StandardGamepadButton `json:"buttons"`
+	Axes    []AxisBinding                  `json:"axes"`
+}
+
+// Describe texto corto para mostrar la asignacion en el menu
+func (b *Binding) Describe() string {
+	var partes []string
+	for _, k := range b.Keys {
+		partes = append(partes, k.String())
+	}
+	for _, btn := range b.Buttons {
+		partes = append(partes, fmt.Sprintf("Boton %d", btn))
+	}
+	if len(partes) == 0 {
+		return "sin asignar"
+	}
+	return strings.Join(partes, ", ")
+}
+
+// Bindings asignaciones de todas las acciones
+type Bindings struct {
+	Actions  map[Action]*Binding `json:"actions"`
+	Deadzone float64             `json:"deadzone"` // zona muerta de los ejes analogicos (0 a 1)
+}
+
+// DefaultBindings flechas y WASD, cruceta y palanca izquierda del control
+func DefaultBindings() *Bindings {
+	return &Bindings{
+		Deadzone: DefaultDeadzone,
+		Actions: map[Action]*Binding{
+			ActionUp: {
+				Keys:    []ebiten.Key{ebiten.KeyArrowUp, ebiten.KeyW},
+				Buttons: []ebiten.StandardGamepadButton{ebiten.StandardGamepadButtonLeftTop},
+				Axes:    []AxisBinding{{Axis: ebiten.StandardGamepadAxisLeftStickVertical, Sign: -1}},
+			},
+			ActionDown: {
+				Keys:    []ebiten.Key{ebiten.KeyArrowDown, ebiten.KeyS},
+				Buttons: []ebiten.StandardGamepadButton{ebiten.StandardGamepadButtonLeftBottom},
+				Axes:    []AxisBinding{{Axis: ebiten.StandardGamepadAxisLeftStickVertical, Sign: 1}},
+			},
+			ActionLeft: {
+				Keys:    []ebiten.Key{ebiten.KeyArrowLeft, ebiten.KeyA},
+				Buttons: []ebiten.StandardGamepadButton{ebiten.StandardGamepadButtonLeftLeft},
+				Axes:    []AxisBinding{{Axis: ebiten.StandardGamepadAxisLeftStickHorizontal, Sign: -1}},
+			},
+			ActionRight: {
+				Keys:    []ebiten.Key{ebiten.KeyArrowRight, ebiten.KeyD},
+				Buttons: []ebiten.StandardGamepadButton{ebiten.StandardGamepadButtonLeftRight},
+				Axes:    []AxisBinding{{Axis: ebiten.StandardGamepadAxisLeftStickHorizontal, Sign: 1}},
+			},
+			ActionPause: {
+				Keys:    []ebiten.Key{ebiten.KeyEscape, ebiten.KeyP},
+				Buttons: []ebiten.StandardGamepadButton{ebiten.StandardGamepadButtonCenterRight},
+			},
+			ActionConfirm: {
+				Keys:    []ebiten.Key{ebiten.KeyEnter, ebiten.KeySpace},
+				Buttons: []ebiten.StandardGamepadButton{ebiten.StandardGamepadButtonRightBottom},
+			},
+		},
+	}
+}
+
+// DefaultTwoPlayerBindings el primer jugador usa las flechas y el segundo WASD,
+// los botones y ejes son los mismos porque cada uno usa su propio control
+func DefaultTwoPlayerBindings() [2]*Bindings {
+	teclas := [2][]ebiten.Key{
+		{ebiten.KeyArrowUp, ebiten.KeyArrowDown, ebiten.KeyArrowLeft, ebiten.KeyArrowRight},
+		{ebiten.KeyW, ebiten.KeyS, ebiten.KeyA, ebiten.KeyD},
+	}
+
+	var jugadores [2]*Bindings
+	for i := range jugadores {
+		b := DefaultBindings()
+		for k, a := range []Action{ActionUp, ActionDown, ActionLeft, ActionRight} {
+			b.Actions[a].Keys = []ebiten.Key{teclas[i][k]}
+		}
+		// pausa y aceptar solo desde el control, el teclado lo comparten
+		b.Actions[ActionPause].Keys = nil
+		b.Actions[ActionConfirm].Keys = nil
+		jugadores[i] = b
+	}
+	return jugadores
+}
+
+// DeviceInput lee el teclado y los controles conectados segun las asignaciones
+type DeviceInput struct {
+	Bindings *Bindings
+	Gamepad  int // -1 lee todos los controles, si no solo el control conectado en esa posicion
+
+	gamepads []ebiten.GamepadID
+	ejes     map[Action]bool // acciones activadas por un eje en este tick
+	ejesAnt  map[Action]bool // acciones activadas por un eje en el tick anterior
+}
+
+func NewDeviceInput(bindings *Bindings) *DeviceInput {
+	return &DeviceInput{
+		Bindings: bindings,
+		Gamepad:  -1,
+		ejes:     map[Action]bool{},
+		ejesAnt:  map[Action]bool{},
+	}
+}
+
+// Update se llama una vez por tick, los ejes no tienen "recien presionado"
+// asi que lo calculamos comparando con el tick anterior
+func (d *DeviceInput) Update() {
+	d.gamepads = ebiten.AppendGamepadIDs(d.gamepads[:0])
+	if d.Gamepad >= 0 {
+		if d.Gamepad < len(d.gamepads) {
+			d.gamepads = d.gamepads[d.Gamepad : d.Gamepad+1]
+		} else {
+			d.gamepads = d.gamepads[:0]
+		}
+	}
+
+	d.ejes, d.ejesAnt = d.ejesAnt, d.ejes
+	clear(d.ejes)
+
+	for accion, b := range d.Bindings.Actions {
+		for _, eje := range b.Axes {
+			for _, id := range d.gamepads {
+				if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+					continue
+				}
+				if ebiten.StandardGamepadAxisValue(id, eje.Axis)*eje.Sign > d.Bindings.Deadzone {
+					d.ejes[accion] = true
+				}
+			}
+		}
+	}
+}
+
+func (d *DeviceInput) Pressed(a Action) bool {
+	b, ok := d.Bindings.Actions[a]
+	if !ok {
+		return false
+	}
+
+	for _, k := range b.Keys {
+		if ebiten.IsKeyPressed(k) {
+			return true
+		}
+	}
+	for _, id := range d.gamepads {
+		for _, btn := range b.Buttons {
+			if ebiten.IsStandardGamepadButtonPressed(id, btn) {
+				return true
+			}
+		}
+	}
+	return d.ejes[a]
+}
+
+func (d *DeviceInput) JustPressed(a Action) bool {
+	b, ok := d.Bindings.Actions[a]
+	if !ok {
+		return false
+	}
+
+	for _, k := range b.Keys {
+		if inpututil.IsKeyJustPressed(k) {
+			return true
+		}
+	}
+	for _, id := range d.gamepads {
+		for _, btn := range b.Buttons {
+			if inpututil.IsStandardGamepadButtonJustPressed(id, btn) {
+				return true
+			}
+		}
+	}
+	return d.ejes[a] && !d.ejesAnt[a]
+}
+
+// JustPressedAny regresa la primera tecla o boton recien presionado, se usa para reasignar
+func (d *DeviceInput) JustPressedAny() (*ebiten.Key, *ebiten.StandardGamepadButton) {
+	if teclas := inpututil.AppendJustPressedKeys(nil); len(teclas) > 0 {
+		return &teclas[0], nil
+	}
+	for _, id := range d.gamepads {
+		if botones := inpututil.AppendJustPressedStandardGamepadButtons(id, nil); len(botones) > 0 {
+			return nil, &botones[0]
+		}
+	}
+	return nil, nil
+}
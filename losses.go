@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Loss funcion de perdida con la que se entrena la red
+type Loss interface {
+	Name() string
+	// Loss suma de la perdida de todos los valores de las filas
+	Loss(yd, y mat.Matrix) float64
+	// Gradient derivada de la perdida respecto a la salida obtenida
+	Gradient(yd, y mat.Matrix) *mat.Dense
+	// GradientInto igual que Gradient pero escribe en dst, se usa al entrenar por lotes
+	GradientInto(dst *mat.Dense, yd, y mat.Matrix)
+}
+
+// aplicarPar aplica f a cada par (deseado, obtenido) y lo guarda en dst
+func aplicarPar(dst *mat.Dense, yd, y mat.Matrix, f func(d, o float64) float64) {
+	dst.Apply(func(i, j int, v float64) float64 {
+		return f(yd.At(i, j), v)
+	}, y)
+}
+
+// gradiente crea la matriz del resultado para las versiones que no reusan memoria
+func gradiente(l Loss, yd, y mat.Matrix) *mat.Dense {
+	r, c := y.Dims()
+	res := mat.NewDense(r, c, nil)
+	l.GradientInto(res, yd, y)
+	return res
+}
+
+func sumarPar(yd, y mat.Matrix, f func(d, o float64) float64) float64 {
+	r, c := y.Dims()
+	suma := 0.0
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			suma += f(yd.At(i, j), y.At(i, j))
+		}
+	}
+	return suma
+}
+
+// MSELoss error cuadratico, 0.5 * (yd - y)^2 igual que el ECM original
+type MSELoss struct{}
+
+func (MSELoss) Name() string {
+	return "mse"
+}
+
+func (MSELoss) Loss(yd, y mat.Matrix) float64 {
+	return sumarPar(yd, y, func(d, o float64) float64 {
+		return 0.5 * (d - o) * (d - o)
+	})
+}
+
+func (l MSELoss) Gradient(yd, y mat.Matrix) *mat.Dense {
+	return gradiente(l, yd, y)
+}
+
+func (MSELoss) GradientInto(dst *mat.Dense, yd, y mat.Matrix) {
+	aplicarPar(dst, yd, y, func(d, o float64) float64 {
+		return o - d
+	})
+}
+
+// MAELoss error absoluto, menos sensible a valores atipicos
+type MAELoss struct{}
+
+func (MAELoss) Name() string {
+	return "mae"
+}
+
+func (MAELoss) Loss(yd, y mat.Matrix) float64 {
+	return sumarPar(yd, y, func(d, o float64) float64 {
+		return math.Abs(d - o)
+	})
+}
+
+func (l MAELoss) Gradient(yd, y mat.Matrix) *mat.Dense {
+	return gradiente(l, yd, y)
+}
+
+func (MAELoss) GradientInto(dst *mat.Dense, yd, y mat.Matrix) {
+	aplicarPar(dst, yd, y, func(d, o float64) float64 {
+		switch {
+		case o > d:
+			return 1
+		case o < d:
+			return -1
+		}
+		return 0
+	})
+}
+
+// HuberLoss cuadratica cerca del valor deseado y lineal lejos de el
+type HuberLoss struct {
+	Delta float64 // distancia a partir de la cual se vuelve lineal
+}
+
+func (HuberLoss) Name() string {
+	return "huber"
+}
+
+func (h HuberLoss) Loss(yd, y mat.Matrix) float64 {
+	return sumarPar(yd, y, func(d, o float64) float64 {
+		e := math.Abs(d - o)
+		if e <= h.Delta {
+			return 0.5 * e * e
+		}
+		return h.Delta * (e - 0.5*h.Delta)
+	})
+}
+
+func (h HuberLoss) Gradient(yd, y mat.Matrix) *mat.Dense {
+	return gradiente(h, yd, y)
+}
+
+func (h HuberLoss) GradientInto(dst *mat.Dense, yd, y mat.Matrix) {
+	aplicarPar(dst, yd, y, func(d, o float64) float64 {
+		return math.Max(-h.Delta, math.Min(h.Delta, o-d))
+	})
+}
+
+// CrossEntropyLoss entropia cruzada, se usa con una salida softmax o sigmoide
+type CrossEntropyLoss struct{}
+
+func (CrossEntropyLoss) Name() string {
+	return "entropia_cruzada"
+}
+
+// recortamos la salida para no calcular log(0)
+func recortarProbabilidad(o float64) float64 {
+	return math.Max(CrossEntropyEpsilon, math.Min(1-CrossEntropyEpsilon, o))
+}
+
+// con una sola salida se usa la forma binaria, que tambien penaliza la clase 0
+func (CrossEntropyLoss) Loss(yd, y mat.Matrix) float64 {
+	_, c := y.Dims()
+	return sumarPar(yd, y, func(d, o float64) float64 {
+		o = recortarProbabilidad(o)
+		if c == 1 {
+			return -d*math.Log(o) - (1-d)*math.Log(1-o)
+		}
+		return -d * math.Log(o)
+	})
+}
+
+func (l CrossEntropyLoss) Gradient(yd, y mat.Matrix) *mat.Dense {
+	return gradiente(l, yd, y)
+}
+
+func (CrossEntropyLoss) GradientInto(dst *mat.Dense, yd, y mat.Matrix) {
+	_, c := y.Dims()
+	aplicarPar(dst, yd, y, func(d, o float64) float64 {
+		o = recortarProbabilidad(o)
+		if c == 1 {
+			return (o - d) / (o * (1 - o))
+		}
+		return -d / o
+	})
+}
+
+// LossByName regresa la funcion de perdida por su nombre
+func LossByName(nombre string) (Loss, error) {
+	switch nombre {
+	case "mse":
+		return MSELoss{}, nil
+	case "mae":
+		return MAELoss{}, nil
+	case "huber":
+		return HuberLoss{Delta: HuberDelta}, nil
+	case "entropia_cruzada":
+		return CrossEntropyLoss{}, nil
+	}
+	return nil, fmt.Errorf("funcion de perdida desconocida: %s", nombre)
+}
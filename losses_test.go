@@ -0,0 +1,61 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// probabilidades filas de softmax, suman 1
+func probabilidades(r, c int) *mat.Dense {
+	return Softmax{}.Forward(matrizAleatoria(r, c))
+}
+
+// unoCaliente una clase al azar por fila
+func unoCaliente(r, c int) *mat.Dense {
+	m := mat.NewDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		m.Set(i, rand.Intn(c), 1)
+	}
+	return m
+}
+
+func TestPerdidasGradiente(t *testing.T) {
+	casos := []struct {
+		nombre  string
+		perdida Loss
+		yd, y   *mat.Dense
+	}{
+		{"mse", MSELoss{}, matrizAleatoria(4, 2), matrizAleatoria(4, 2)},
+		// ninguna salida es igual a la deseada, mae no tiene derivada con error cero
+		{"mae", MAELoss{}, mat.NewDense(2, 2, []float64{1, -1, 2, -2}), mat.NewDense(2, 2, []float64{-0.5, 0.3, 0.5, -3})},
+		// errores de ambos lados de delta
+		{"huber", HuberLoss{Delta: HuberDelta}, mat.NewDense(1, 4, []float64{0, 0, 1, 1}), mat.NewDense(1, 4, []float64{0.3, -2.5, 0.2, 4})},
+		{"entropia_cruzada_binaria", CrossEntropyLoss{}, mat.NewDense(4, 1, []float64{0, 1, 1, 0}), mat.NewDense(4, 1, []float64{0.2, 0.7, 0.4, 0.9})},
+		{"entropia_cruzada", CrossEntropyLoss{}, unoCaliente(3, 4), probabilidades(3, 4)},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nombre, func(t *testing.T) {
+			numerico := gradienteNumerico(caso.y, func() float64 {
+				return caso.perdida.Loss(caso.yd, caso.y)
+			})
+			analitico := caso.perdida.Gradient(caso.yd, caso.y)
+			compararMatrices(t, "gradiente", analitico, numerico, toleranciaGradient)
+
+			r, c := caso.y.Dims()
+			dst := mat.NewDense(r, c, nil)
+			caso.perdida.GradientInto(dst, caso.yd, caso.y)
+			compararMatrices(t, "gradiente_into", dst, analitico, 0)
+		})
+	}
+}
+
+func TestPerdidaCero(t *testing.T) {
+	y := matrizAleatoria(3, 2)
+	for _, l := range []Loss{MSELoss{}, MAELoss{}, HuberLoss{Delta: HuberDelta}} {
+		if p := l.Loss(y, y); p != 0 {
+			t.Errorf("%s con la salida igual a la deseada = %g, se esperaba 0", l.Name(), p)
+		}
+	}
+}
@@ -8,13 +8,15 @@ import (
 	"io"
 	"log"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/audio"
 	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
-	"gonum.org/v1/gonum/mat"
 	"gorm.io/gorm"
 )
 
@@ -23,6 +25,8 @@ type State int
 const (
 	PlayingState State = iota
 	GameOverState
+	PausedState // menu de pausa
+	RebindState // esperando la tecla o boton para reasignar una accion
 )
 
 //go:embed assets
@@ -46,33 +50,74 @@ type Font struct {
 	Options *text.DrawOptions
 }
 type Game struct {
-	Maze          Maze         // guarda la matriz del mapa del juego
-	Dimensiones   *Dimensiones // guarda las dimensiones del mapa del juego
-	Player        *Player      // guarda el objeto del datos del jugador
-	IsMoving      bool         // indica si se esta moviendo en el mapa
-	MazeAssets    *MazeAssets  // contiene texturas para el renderizado del mapa
+	Maze          Maze           // guarda la matriz del mapa del juego
+	Dimensiones   *Dimensiones   // guarda las dimensiones del mapa del juego
+	Players       []*Player      // jugadores que recorren el laberinto, en solo hay uno
+	Mode          PlayMode       // solo, cooperativo o versus
+	Dog           *Enemy         // en versus, el perro que controla el segundo jugador
+	MatchID       string         // identifica la partida, lo comparten los registros de cada jugador
+	PlayerInputs  []*DeviceInput // teclado y control de cada jugador
+	IsMoving      bool           // indica si se esta moviendo en el mapa
+	MazeAssets    *MazeAssets    // contiene texturas para el renderizado del mapa
 	Enemys        Enemys
 	State         State     // indica el estado actual del juego, si esta jugado o ha terminad
 	Font          *Font     // fuente para renderizar en el juego
 	StartTime     time.Time // indica cuando empezo la partida del jugador
 	DB            *gorm.DB
 	CoinSoundData []byte
+	Modes         *ModeScheduler // linea de tiempo de modos que comparten los enemigos
+	Coordinator   *Coordinator   // reparte a los enemigos para flanquear al jugador
+	Spawner       *Spawner       // agrega oleadas de enemigos durante la partida
+	Input         *DeviceInput   // teclado y controles, tambien maneja el menu
+	Settings      *Settings      // configuracion guardada del jugador
+	Menu          *Menu
+	Effects       PowerUpEffects // power-ups activos
+	Ticks         int            // ticks jugados en la partida
+	Headless      bool           // sin ventana, no se cargan imagenes ni sonidos
+	Bot           bool           // los jugadores los maneja el bot
+	Truncada      bool           // se corto por tiempo, no termino normalmente
+	Laberinto     MazeStats      // forma del laberinto al empezar, antes de que rompe_muros quite paredes
+	// pixeles que se recortan de cada lado de las cajas de colision,
+	// entre mas grande, mas cerca puede pasar un enemigo sin atrapar al jugador
+	CollisionForgiveness float64
 }
 
-func (j *Game) NewEnemy(position *Node, elapse int) {
+// NewEnemy crea un enemigo del tipo dado, el ajuste desplaza el lapso base del tipo
+func (j *Game) NewEnemy(position *Node, tipo *EnemyArchetype, ajuste int) (*Enemy, error) {
+	speed := tipo.Speed(ajuste)
+	habilidad, err := NewEnemyAbility(tipo.Ability, tipo.AbilityEvery)
+	if err != nil {
+		return nil, err
+	}
+
 	e := &Enemy{
-		NodePosition:    position,       // columnas, filas, se considera que n-1 menos el los muros
-		Elapse:          EnemyElapseMax, // cada cierto ciclos va recalcular la ruta al enemigo
-		ElapseDecrement: elapse,         // cada punto cuesta un una parte del recorrido
+		NodePosition:    position,              // columnas, filas, se considera que n-1 menos el los muros
+		Elapse:          speed.Elapse,          // cada cierto ciclos va recalcular la ruta al enemigo
+		ElapseDecrement: speed.ElapseDecrement, // cada punto cuesta un una parte del recorrido
+		InitialElapse:   speed.Elapse,
+		MinElapse:       speed.MinElapse,
 		PathIndex:       1,
+		Archetype:       tipo,
+		Behavior:        EnemyBehaviors[tipo.Behavior](j, position),
+		Ability:         habilidad,
+		Spawn:           position.Clone(),
+		HomeCorner:      position.Clone(), // se dispersa hacia la esquina donde aparece
+		SightRange:      EnemySightRange,
+		SpawnTicks:      EnemySpawnTicks, // aparece con una animacion
+		Facing:          DirectionRight,
+		FacingScaleX:    1,
 	}
 
-	e.Animation = NewAnimation(&AnimationOption{
-		Assets:         assetsFS,
-		Indexes:        [2]int{0, 11},
-		TemplateString: "assets/dog/f_%d.png",
-		Elapse:         TPS * .25,
-	})
+	// igual que el jugador, tiene una animacion quieto y otra caminando,
+	// ademas de las animaciones propias de cada direccion que traiga el tipo
+	e.DirectionAnimations = map[Direction]*Animation{}
+	if !j.Headless {
+		e.MovingAnimation = tipo.Walk().Load()
+		e.StayAnimation = tipo.IdleSpec().Load()
+		for nombre, a := range tipo.Directions {
+			e.DirectionAnimations[DirectionNames[nombre]] = a.Load()
+		}
+	}
 
 	e.VectorCurrentPosition = NewVector(
 		float64(e.NodePosition.X*squareSize),
@@ -81,63 +126,176 @@ func (j *Game) NewEnemy(position *Node, elapse int) {
 
 	e.Juego = j
 	j.Enemys = append(j.Enemys, e)
+	return e, nil
 }
 
 // MovePlayer se encarga de crear de calcular las frames actuales Y las posiciones vectoriales
-func (j *Game) MovePlayer() {
-	p := j.Player
-
+func (j *Game) MovePlayer(p *Player) {
 	// continuar movimiento en progreso
 	if p.IsMoving {
 		p.Moving()
 	}
 
-	// detectar nuevas teclas solo si NO está moviéndose
+	// con el buffer la direccion se lee aun en movimiento y se guarda hasta poder tomarla,
+	// sin el solo se mueve si la tecla esta presionada justo al llegar a la celda
+	if j.Settings.BufferInput {
+		p.ReadDesiredDirection()
+	}
+
+	// detectar nuevas acciones solo si NO está moviéndose
 	if !p.IsMoving {
-		if ebiten.IsKeyPressed(ebiten.KeyArrowUp) {
-			p.MoveToUp()
-		} else if ebiten.IsKeyPressed(ebiten.KeyArrowDown) {
-			p.MoveToDown()
-		} else if ebiten.IsKeyPressed(ebiten.KeyArrowLeft) {
-			p.MoveToLeft()
-		} else if ebiten.IsKeyPressed(ebiten.KeyArrowRight) {
-			p.MoveToRight()
+		if !j.Settings.BufferInput {
+			p.HasDesired = false
+			for _, da := range directionActions {
+				if p.Input.Pressed(da.Action) {
+					p.DesiredDirection = da.Direction
+					p.HasDesired = true
+					break
+				}
+			}
 		}
+		p.Steer(j.Settings.AutoRun)
 	}
 
 	p.Tick() // Avanzar animaciones
 
 	// tenemos que validar si el nodo si encuentra es un ajolote punto
 
-	if j.Maze.Get(j.Player.NodePosition.X, j.Player.NodePosition.Y) == AjolotePointType {
+	if j.Maze.Get(p.NodePosition.X, p.NodePosition.Y) == AjolotePointType {
 		p.Points += AjolotePointValue
+		p.Ajolotes++
 		// ajustamos el intervalor de tiempo para aumentar dificultad
 		for _, e := range j.Enemys {
-			e.Elapse -= e.ElapseDecrement
+			e.SpeedUp()
 		}
-		j.Maze.Set(j.Player.NodePosition.X, j.Player.NodePosition.Y, Transitable) // indicamos que ya solo es camino
+		j.Maze.Set(p.NodePosition.X, p.NodePosition.Y, Transitable) // indicamos que ya solo es camino
 		// sonamos que tomo una ajolote point
 		j.playCoinSound()
+		// cada cierto numero de ajolotes los enemigos se asustan
+		if j.TotalAjolotes()%FrightenedEveryAjolotes == 0 {
+			j.FrightenEnemies()
+		}
+	}
+
+	if pu, ok := PowerUpFromCell(j.Maze.Get(p.NodePosition.X, p.NodePosition.Y)); ok {
+		j.Maze.Set(p.NodePosition.X, p.NodePosition.Y, Transitable)
+		j.playCoinSound()
+		j.CollectPowerUp(p, pu)
 	}
 }
 
+// FrightenEnemies asusta a todos los enemigos, incluso a los que ya habian sido comidos
+func (j *Game) FrightenEnemies() {
+	j.Modes.Frighten(FrightenedTicks)
+	for _, e := range j.Enemys {
+		e.Eaten = false
+	}
+}
+
+// EatEnemy el jugador se come a un enemigo asustado, este regresa a su punto de aparicion
+func (j *Game) EatEnemy(p *Player, e *Enemy) {
+	p.Points += EnemyEatenValue
+	e.Eaten = true
+	e.Respawn()
+}
+
 func (j *Game) MoveEnemy() {
 	//e := j.Enemy
 	//e.Tick() // avanzar animaciones del enemigo
+	if CooperativePlanning {
+		j.Coordinator.Plan(j)
+	}
 	for _, e := range j.Enemys {
 		e.Tick()
 	}
 }
 
+// LoseLife el jugador fue atrapado: reaparece junto con los enemigos,
+// sin vidas queda fuera y la partida termina cuando ya no queda ningun jugador
+func (j *Game) LoseLife(p *Player, e *Enemy) {
+	j.RecordDeath(p, e)
+	p.Lives--
+	p.LivesUsed++
+	if p.Lives <= 0 {
+		p.Out = true
+		if len(j.ActivePlayers()) == 0 {
+			j.GameOver()
+		}
+		return
+	}
+
+	p.Respawn()
+
+	// los enemigos cuyo punto de aparicion quedo junto al jugador reaparecen en el mas lejano
+	seguros := j.Spawner.SafePoints(j)
+	for _, e := range j.Enemys {
+		if len(seguros) > 0 && !slices.ContainsFunc(seguros, e.Spawn.Equal) {
+			e.Spawn = seguros[0].Clone()
+		}
+		e.Respawn()
+	}
+}
+
 func (j *Game) GameOver() {
 	// tenemos que registrar el puntaje del jugados
-	diff := time.Now().Sub(j.StartTime).Seconds()
-	e := j.Enemys[0] // tomamos el primer enemigo, todos comparten el mismo dato
-	err := j.DB.Create(&GameScore{
-		Velocity: e.Elapse,
-		Score:    j.Player.Points,
-		Time:     diff,
-	}).Error
+	diff := j.ElapsedSeconds()
+
+	// cada enemigo tiene su propia curva, guardamos el desglose de cada uno
+	// y como velocidad general el promedio de sus lapsos finales
+	enemigos := make([]EnemyScore, len(j.Enemys))
+	suma := 0
+	for i, e := range j.Enemys {
+		enemigos[i] = EnemyScore{
+			Index:           i,
+			Archetype:       e.Archetype.Name,
+			Behavior:        e.Behavior.Name(),
+			InitialElapse:   e.InitialElapse,
+			FinalElapse:     e.Elapse,
+			ElapseDecrement: e.ElapseDecrement,
+			MinElapse:       e.MinElapse,
+		}
+		suma += e.Elapse
+	}
+
+	// cada jugador tiene su propio registro, el desglose de enemigos va en el del primero
+	var registros []GameScore
+	for i, p := range j.Players {
+		registro := GameScore{
+			Velocity:    suma / max(len(j.Enemys), 1),
+			Score:       p.Points,
+			Time:        diff,
+			LivesUsed:   p.LivesUsed,
+			PowerUps:    p.PowerUps,
+			MatchID:     j.MatchID,
+			PlayerIndex: i,
+			Role:        RoleRunner,
+			Mode:        j.Mode.String(),
+			Bot:         j.Bot,
+			Truncada:    j.Truncada,
+			Telemetry:   p.Telemetry.Resumen(p.Ajolotes, j.Laberinto),
+			Deaths:      p.Telemetry.Deaths,
+		}
+		if i == 0 {
+			registro.Enemies = enemigos
+		}
+		registros = append(registros, registro)
+	}
+
+	// en versus el perro del segundo jugador tambien guarda su puntaje
+	if j.Dog != nil {
+		registros = append(registros, GameScore{
+			Velocity:    j.Dog.Elapse,
+			Score:       j.Dog.Points,
+			Time:        diff,
+			MatchID:     j.MatchID,
+			PlayerIndex: len(j.Players),
+			Role:        RoleDog,
+			Mode:        j.Mode.String(),
+			Truncada:    j.Truncada,
+		})
+	}
+
+	err := j.DB.Create(&registros).Error
 
 	if err != nil {
 		log.Fatal(err)
@@ -175,25 +333,102 @@ func (j *Game) DrawMaze(screen *ebiten.Image) {
 				screen.DrawImage(j.MazeAssets.Floor, imgOptions)
 			}
 
+			// los power-ups se dibujan sobre el piso
+			if pu, ok := PowerUpFromCell(celda); ok {
+				screen.DrawImage(j.MazeAssets.Floor, imgOptions)
+				j.DrawPowerUp(screen, pu, x, y)
+				continue
+			}
+
 			screen.DrawImage(mazeAsset, imgOptions)
 		}
 	}
 }
 
+// ElapsedSeconds tiempo jugado, sin ventana se cuenta por ticks
+// porque la partida corre tan rapido como se pueda
+func (j *Game) ElapsedSeconds() float64 {
+	if j.Headless {
+		return float64(j.Ticks) / TPS
+	}
+	return time.Since(j.StartTime).Seconds()
+}
+
 func (j *Game) Update() error {
+	if j.Input != nil {
+		j.Input.Update()
+	}
+	for _, in := range j.PlayerInputs {
+		in.Update()
+	}
+
+	switch j.State {
+	case PausedState:
+		j.UpdateMenu()
+		return nil
+	case RebindState:
+		j.UpdateRebind()
+		return nil
+	}
+
 	if j.State == PlayingState {
-		j.MovePlayer()
+		if j.Input != nil && j.Input.JustPressed(ActionPause) {
+			j.Pause()
+			return nil
+		}
+
+		j.Ticks++
+
+		j.Modes.Tick()
+		j.Effects.Tick()
+		for _, p := range j.Players {
+			if p.InvulnerableTicks > 0 {
+				p.InvulnerableTicks--
+			}
+		}
+		if err := j.Spawner.Tick(j); err != nil {
+			return err
+		}
+		for _, p := range j.ActivePlayers() {
+			j.MovePlayer(p)
+		}
 		j.MoveEnemy()
-		// validamos si tanto el enemigo como el jugador llegaron a colisionar si estan en
-		// en el mismo punto (nodo)
+		j.RecordTelemetry()
+
+		// validamos si el enemigo y el jugador colisionan segun sus posiciones interpoladas,
+		// asi no se atraviesan al cruzarse entre celdas ni se atrapa antes de tocarse
+		for _, p := range j.ActivePlayers() {
+			cajaJugador := p.Hitbox(j.CollisionForgiveness)
 
-		for _, e := range j.Enemys { // validamos si alguno de los enemigos toca al jugador
-			if e.NodePosition.Equal(j.Player.NodePosition) || j.Player.Points == MaxAjolotePoints {
-				// indicamos que tenemos que acabar el juego
-				j.GameOver()
+			for _, e := range j.Enemys { // validamos si alguno de los enemigos toca al jugador
+				if e.IsSpawning() {
+					continue // mientras aparece no puede atrapar ni ser comido
+				}
+				if !cajaJugador.Intersects(e.Hitbox(j.CollisionForgiveness)) {
+					continue
+				}
+				// si esta asustado el jugador se lo come
+				if e.Mode() == FrightenedMode {
+					j.EatEnemy(p, e)
+					continue
+				}
+				if p.IsInvulnerable() {
+					continue
+				}
+				// el perro del segundo jugador gana puntos por atraparlo
+				if e == j.Dog {
+					e.Points += DogCatchValue
+				}
+				// pierde una vida, si era la ultima queda fuera
+				j.LoseLife(p, e)
+				return nil
 			}
 		}
 
+		if j.TotalAjolotes() == NumAjolotes {
+			j.GameOver()
+		}
+
 	}
 
 	return nil
@@ -202,11 +437,13 @@ func (j *Game) Update() error {
 func (j *Game) Draw(screen *ebiten.Image) {
 
 	// dibujamos le puntaje
-	if j.State == PlayingState {
+	if j.State == PlayingState || j.State == PausedState || j.State == RebindState {
 		j.DrawMaze(screen)
 		// dibujamos el jugaodor
 		// lo colocamos en medio de la celda
-		j.Player.DrawPlayer(screen)
+		for _, p := range j.ActivePlayers() {
+			p.DrawPlayer(screen)
+		}
 
 		//j.Enemy.Draw(screen)
 
@@ -215,17 +452,42 @@ func (j *Game) Draw(screen *ebiten.Image) {
 		}
 
 		// animacion para los ajolote poins
-		j.MazeAssets.AjoloteAnimation.Tick()
+		if j.State == PlayingState {
+			j.MazeAssets.AjoloteAnimation.Tick()
+		}
 
 	} else if j.State == GameOverState {
 		// dibujamos el games over
 
 	}
 
-	text.Draw(screen, fmt.Sprintf("Puntaje: %d", j.Player.Points), j.Font.Face, j.Font.Options)
+	// puntaje y vidas de cada jugador, en versus tambien el del perro
+	puntajes := make([]string, len(j.Players))
+	vidas := make([]string, len(j.Players))
+	for i, p := range j.Players {
+		puntajes[i] = strconv.Itoa(int(p.Points))
+		vidas[i] = strconv.Itoa(p.Lives)
+	}
+	if j.Dog != nil {
+		puntajes = append(puntajes, fmt.Sprintf("perro %d", j.Dog.Points))
+	}
+	text.Draw(screen, "Puntaje: "+strings.Join(puntajes, " | "), j.Font.Face, j.Font.Options)
 	fontVelocidad := *j.Font.Options
 	fontVelocidad.GeoM.Translate(300, 0)
-	text.Draw(screen, fmt.Sprintf("Velocidad: %d", j.Enemys[0].Elapse), j.Font.Face, &fontVelocidad)
+	// mostramos la velocidad de cada perro
+	velocidades := make([]string, len(j.Enemys))
+	for i, e := range j.Enemys {
+		velocidades[i] = strconv.Itoa(e.Elapse)
+	}
+	text.Draw(screen, "Velocidad: "+strings.Join(velocidades, " | "), j.Font.Face, &fontVelocidad)
+	fontVidas := *j.Font.Options
+	fontVidas.GeoM.Translate(0, FontSize*2.5)
+	text.Draw(screen, "Vidas: "+strings.Join(vidas, " | "), j.Font.Face, &fontVidas)
+	j.DrawEffects(screen)
+
+	if j.State == PausedState || j.State == RebindState {
+		j.DrawMenu(screen)
+	}
 }
 
 func (j *Game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
@@ -270,6 +532,10 @@ func PlayMusic() {
 
 // Función auxiliar para reproducir el sonido
 func (g *Game) playCoinSound() {
+	if g.Headless {
+		return
+	}
+
 	// 3. Crear un reproductor "desechable" desde la memoria
 	// NewPlayerFromBytes es muy ligero y eficiente para SFX
 	ctx := audio.CurrentContext()
@@ -286,23 +552,159 @@ func (g *Game) playCoinSound() {
 	player.Play()
 }
 
+// NewGame crea una partida con un laberinto nuevo, sin ventana no carga imagenes ni sonidos
+func NewGame(db *gorm.DB, settings *Settings, predictedElapse int, headless bool, modo PlayMode) (*Game, error) {
+	mapa := NewMaze(Columnas, Filas)
+
+	Mazerand(mapa)
+
+	juego := &Game{
+		Maze:        mapa,
+		Dimensiones: &Dimensiones{},
+		State:       PlayingState,
+		DB:          db,
+		StartTime:   time.Now(),
+		Modes:       NewModeScheduler(DefaultModeTimeline),
+		Coordinator: NewCoordinator(),
+		Headless:    headless,
+		Mode:        modo,
+		MatchID:     NewMatchID(),
+
+		CollisionForgiveness: CollisionForgiveness,
+
+		Settings: settings,
+		Menu:     &Menu{},
+		Effects:  PowerUpEffects{},
+	}
+
+	f, c := juego.Maze.GetShape()
+
+	juego.Dimensiones.Alto = f * squareSize
+	juego.Dimensiones.Ancho = c * squareSize
+	juego.Dimensiones.Filas = f
+	juego.Dimensiones.Columnas = c
+
+	// el primer jugador empieza arriba a la izquierda, en cooperativo el segundo arriba a la derecha
+	inicios := []*Node{NewNode(1, 1)}
+	if modo == CoopMode {
+		inicios = append(inicios, mapa.NearestWalkable(c-2, 1))
+	}
+
+	for i, puntoInicial := range inicios {
+		jugador := NewPlayer()
+		jugador.Index = i
+
+		startPosition := NewVector(float64(puntoInicial.X*squareSize), float64(puntoInicial.Y*squareSize))
+
+		jugador.CurrentPosition = startPosition.Clone()
+		jugador.TargetPosition = startPosition.Clone() // clonamos para evitar escribir la misma direccion de memoria
+		jugador.NodePosition = puntoInicial.Clone()
+		jugador.Spawn = puntoInicial.Clone()
+
+		// para que el jugador tenga acceso al los datos del juego
+		jugador.Game = juego
+		juego.Players = append(juego.Players, jugador)
+	}
+	juego.Laberinto = NewMazeStats(mapa, inicios[0])
+
+	// sin ventana no hay teclado ni controles, los jugadores los maneja un bot
+	if !headless {
+		juego.Input = NewDeviceInput(settings.Bindings)
+		if modo == SoloMode {
+			juego.Players[0].Input = juego.Input
+		} else {
+			// con dos jugadores cada uno tiene sus teclas y su propio control
+			for i, b := range settings.TwoPlayerBindings {
+				in := NewDeviceInput(b)
+				in.Gamepad = i
+				juego.PlayerInputs = append(juego.PlayerInputs, in)
+			}
+			for i, p := range juego.Players {
+				p.Input = juego.PlayerInputs[i]
+			}
+		}
+	}
+
+	// los enemigos aparecen por oleadas, todas parten de la velocidad predicha
+	juego.Spawner = NewSpawner(mapa, DefaultWaves, predictedElapse)
+	// ya que se conocen los inicios y los puntos de aparicion, ningun power-up queda encima de ellos
+	PlacePowerUps(mapa, NumPowerUps, append(slices.Clone(inicios), juego.Spawner.Points...))
+
+	// en versus el segundo jugador maneja un perro desde el inicio
+	if modo == VersusMode {
+		var in InputSource
+		if len(juego.PlayerInputs) > 1 {
+			in = juego.PlayerInputs[1]
+		}
+		if err := juego.SpawnDog(in); err != nil {
+			return nil, err
+		}
+	}
+
+	return juego, nil
+}
+
 func main() {
 	// Manejo de argumentos de línea de comandos
+	if len(os.Args) > 1 && os.Args[1] == "model" {
+		if err := RunModelCommand(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	trainMode := false
 	noPredict := false
+	rlMode := false
+	botMode := false
+	headless := false
+	modo := SoloMode
+	partidas := HeadlessGames
+	opcionesEntrenamiento := DefaultTrainOptions()
 	for _, arg := range os.Args[1:] {
-		if arg == "-e" {
+		if arg == "-e" || arg == "train" {
 			trainMode = true
 		} else if arg == "-n" {
 			noPredict = true
+		} else if arg == "-rl" {
+			rlMode = true
+		} else if arg == "play" {
+			// subcomando por defecto, se acepta para poder escribir play --bot
+		} else if arg == "--bot" {
+			botMode = true
+		} else if arg == "--headless" {
+			headless = true
+		} else if arg == "--coop" {
+			modo = CoopMode
+		} else if arg == "--versus" {
+			modo = VersusMode
+		} else if valor, ok := strings.CutPrefix(arg, "--partidas="); ok {
+			n, err := strconv.Atoi(valor)
+			if err != nil || n <= 0 {
+				log.Fatalf("numero de partidas invalido: %s", valor)
+			}
+			partidas = n
+		} else if ok, err := opcionesEntrenamiento.ParseArg(arg); ok && err != nil {
+			log.Fatal(err)
 		}
 	}
 
 	if trainMode {
-		RunTraining()
+		RunTraining(opcionesEntrenamiento)
 		return
 	}
 
+	if rlMode {
+		RunRLTraining()
+		return
+	}
+
+	// cargamos la configuracion del jugador (controles)
+	settings, err := LoadSettings(SettingsFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// incializamos la base datos
 
 	db, err := OpenDB()
@@ -311,47 +713,24 @@ func main() {
 		log.Fatal(err)
 	}
 
-	if err = db.AutoMigrate(&GameScore{}); err != nil {
+	if err = MigrateDB(db); err != nil {
 		log.Fatal(err)
 	}
 
-	// 3. Predicción de velocidad (lógica solicitada)
-	predictedElapse := EnemyElapseMax // Valor por defecto (Lento)
-
-	if !noPredict {
-		// Verificar si existe el modelo
-		if _, err := os.Stat("modelo_velocidad.gob"); err == nil {
-			// Cargar modelo
-			W, B, err := CargarModelo("modelo_velocidad")
-			if err != nil {
-				log.Printf("Advertencia: No se pudo cargar el modelo: %v\n", err)
-			} else {
-				// Obtener último puntaje
-				var lastGame GameScore
-				// Order by CreatedAt desc, limit 1
-				result := db.Order("created_at desc").First(&lastGame)
-
-				if result.Error == nil {
-					// Predecir
-					// Inputs: [Score, Time]
-					// Nota: El modelo ya incluye la normalización de inputs en W[0], B[0]
-					input := mat.NewDense(1, 2, []float64{float64(lastGame.Score), lastGame.Time})
-					out := Predecir(input, W, B)
-					val := out.At(0, 0) // Salida Sigmoide 0..1
-
-					// Desnormalizar Salida (MinMax)
-					// velocity = val * (Max - Min) + Min
-					predVal := val*float64(EnemyElapseMax-EnemyElapseMin) + float64(EnemyElapseMin)
-					predictedElapse = int(predVal)
-
-					fmt.Printf("¡Modelo cargado! Velocidad predicha para el enemigo: %d (Basado en Score: %d, Time: %.2f)\n", predictedElapse, lastGame.Score, lastGame.Time)
-				} else {
-					log.Println("No se encontraron partidas previas para predecir. Usando velocidad por defecto.")
-				}
-			}
-		}
+	// cargamos los tipos de enemigo, cada uno trae su animacion, velocidad y habilidad
+	if err = LoadArchetypes(assetsFS, "assets/enemies/archetypes.json"); err != nil {
+		log.Fatal(err)
+	}
+
+	// sin ventana solo juega el bot, sirve para probar el balance y generar partidas
+	if headless {
+		RunHeadless(db, settings, noPredict, partidas, modo)
+		return
 	}
 
+	// 3. Predicción de velocidad (lógica solicitada)
+	predictedElapse := PredictElapse(db, settings, noPredict)
+
 	// cargamos la fuente
 	fontFile, err := assetsFS.Open("assets/font.ttf")
 	if err != nil {
@@ -373,82 +752,42 @@ func main() {
 	}
 
 	font.Options.ColorScale.ScaleWithColor(color.RGBA{R: 0, G: 255, B: 0, A: 255})
-	// cargamos los assets
-	puntoInicial := NewNode(1, 1)
-
-	middleX := float64(puntoInicial.X * squareSize)
-	middleY := float64(puntoInicial.Y * squareSize)
-
-	jugador := NewPlayer()
-
-	jugador.MovingAnimation = NewAnimation(
-		&AnimationOption{
-			Assets:         assetsFS,
-			Indexes:        [2]int{0, 9},
-			TemplateString: "assets/nibbit_walking/f_000%d.png",
-			Elapse:         3,
-		},
-	)
-
-	jugador.StayAnimation = NewAnimation(
-		&AnimationOption{
-			Assets:         assetsFS,
-			Indexes:        [2]int{0, 11},
-			TemplateString: "assets/nibbit_staying/fs_%d.png",
-			Elapse:         TPS * 0.2,
-		},
-	)
-
-	startPosition := NewVector(middleX, middleY)
-
-	jugador.CurrentPosition = startPosition.Clone()
-	jugador.TargetPosition = startPosition.Clone() // clonamos para evitar escribir la misma direccion de memoria
-	jugador.NodePosition = NewNode(1, 1)
-
-	mapa := NewMaze(Columnas, Filas)
-
-	Mazerand(mapa)
 
-	juego := &Game{
-		Maze:        mapa,
-		Dimensiones: &Dimensiones{},
-		Player:      jugador,
-		Font:        font,
-		MazeAssets: &MazeAssets{
-			Floor: openAsset(assetsFS, "assets/floor.png"),
-			Wall:  openAsset(assetsFS, "assets/wall.png"),
-		},
-		State:     PlayingState,
-		DB:        db,
-		StartTime: time.Now(),
+	juego, err := NewGame(db, settings, predictedElapse, false, modo)
+	if err != nil {
+		log.Fatal(err)
 	}
+	juego.Font = font
+	juego.Bot = botMode
 
-	// para que el jugador tenga acceso al los datos del juego
-	juego.Player.Game = juego
-
-	f, c := juego.Maze.GetShape()
-
-	juego.Dimensiones.Alto = f * squareSize
-	juego.Dimensiones.Ancho = c * squareSize
-	juego.Dimensiones.Filas = f
-	juego.Dimensiones.Columnas = c
-
-	// cargamos al enemigo
-
-	delta := EnemyElapseMax - EnemyElapseMin // recorrido en minimo y maximo (distancia)
-	pasos := MaxAjolotePoints / delta        // cuantos pasos hay el recorrido, segun cuantos puntos maximos halla
-
-	deltaStep := delta / pasos
+	for _, jugador := range juego.Players {
+		if botMode {
+			jugador.Input = NewAutopilot(juego, jugador, AutopilotSafeDistance)
+		}
 
-	juego.NewEnemy(NewNode(c-2, f-2), deltaStep)
-	juego.NewEnemy(NewNode(c-2, 1), deltaStep)
-	juego.NewEnemy(NewNode(1, f-2), deltaStep)
+		// cargamos los assets, cada jugador lleva su propia animacion
+		jugador.MovingAnimation = NewAnimation(
+			&AnimationOption{
+				Assets:         assetsFS,
+				Indexes:        [2]int{0, 9},
+				TemplateString: "assets/nibbit_walking/f_000%d.png",
+				Elapse:         3,
+			},
+		)
+
+		jugador.StayAnimation = NewAnimation(
+			&AnimationOption{
+				Assets:         assetsFS,
+				Indexes:        [2]int{0, 11},
+				TemplateString: "assets/nibbit_staying/fs_%d.png",
+				Elapse:         TPS * 0.2,
+			},
+		)
+	}
 
-	// Aplicar velocidad predicha
-	if predictedElapse != EnemyElapseMax {
-		for _, e := range juego.Enemys {
-			e.Elapse = predictedElapse
-		}
+	juego.MazeAssets = &MazeAssets{
+		Floor: openAsset(assetsFS, "assets/floor.png"),
+		Wall:  openAsset(assetsFS, "assets/wall.png"),
 	}
 
 	// cargamos la animacion de ajolote pesos
@@ -458,12 +797,6 @@ func main() {
 		TemplateString: "assets/ajolote/f%d.png",
 		Elapse:         AjoloteElapse,
 	})
-	// iniciamos el calculo inicial del enemigo
-	for _, e := range juego.Enemys {
-		e.CalculatePath()
-	}
-	//juego.Enemy.CalculatePath()
-
 	PlayMusic()
 
 	// cargamos el sonido de la moneda
@@ -155,3 +155,206 @@ func (m Maze) Set(x, y, v int) {
 func (m Maze) Get(x, y int) int {
 	return m[y][x]
 }
+
+// Clone copia el laberinto, los cambios a la copia no afectan al original
+func (m Maze) Clone() Maze {
+	copia := make(Maze, len(m))
+	for y, fila := range m {
+		copia[y] = append([]int(nil), fila...)
+	}
+	return copia
+}
+
+// IsWalkableCell indica si el valor de la celda se puede transitar,
+// los ajolote points y los power-ups tambien se consideran transitables
+func IsWalkableCell(celda int) bool {
+	if _, ok := PowerUpFromCell(celda); ok {
+		return true
+	}
+	return celda == Transitable || celda == AjolotePointType
+}
+
+// IsWalkable indica si la celda esta dentro del mapa y se puede transitar
+func (m Maze) IsWalkable(x, y int) bool {
+	return insideXY(m, y, x) && IsWalkableCell(m[y][x])
+}
+
+// NearestWalkable busca (en anchura) la celda transitable mas cercana a (x, y)
+func (m Maze) NearestWalkable(x, y int) *Node {
+	f, c := m.GetShape()
+	x = max(0, min(x, c-1))
+	y = max(0, min(y, f-1))
+
+	visitados := make([][]bool, f)
+	for i := range visitados {
+		visitados[i] = make([]bool, c)
+	}
+
+	cola := []Pos{{y, x}}
+	visitados[y][x] = true
+	dirs := []Pos{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
+
+	for len(cola) > 0 {
+		p := cola[0]
+		cola = cola[1:]
+		if m.IsWalkable(p.X, p.Y) {
+			return NewNode(p.X, p.Y)
+		}
+		for _, d := range dirs {
+			ny, nx := p.Y+d.Y, p.X+d.X
+			if insideXY(m, ny, nx) && !visitados[ny][nx] {
+				visitados[ny][nx] = true
+				cola = append(cola, Pos{ny, nx})
+			}
+		}
+	}
+	return nil
+}
+
+// LineOfSight traza una linea (Bresenham) entre dos nodos,
+// regresa falso si algun muro se interpone entre ellos
+func (m Maze) LineOfSight(a, b *Node) bool {
+	x0, y0 := a.X, a.Y
+	x1, y1 := b.X, b.Y
+
+	dx := abs(x1 - x0)
+	dy := -abs(y1 - y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	err := dx + dy
+
+	for {
+		if !m.IsWalkable(x0, y0) {
+			return false
+		}
+		if x0 == x1 && y0 == y1 {
+			return true
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// DistanceField calcula (en anchura) la distancia en pasos desde el origen
+// a cada celda transitable del mapa, las celdas inalcanzables quedan en -1
+func (m Maze) DistanceField(origen *Node) [][]int {
+	return m.MultiDistanceField([]*Node{origen})
+}
+
+// MultiDistanceField igual que DistanceField pero desde varios origenes a la vez,
+// cada celda guarda la distancia al origen mas cercano
+func (m Maze) MultiDistanceField(origenes []*Node) [][]int {
+	f, c := m.GetShape()
+	distancias := make([][]int, f)
+	for i := range distancias {
+		distancias[i] = make([]int, c)
+		for j := range distancias[i] {
+			distancias[i][j] = -1
+		}
+	}
+
+	var cola []Pos
+	for _, o := range origenes {
+		if m.IsWalkable(o.X, o.Y) && distancias[o.Y][o.X] == -1 {
+			distancias[o.Y][o.X] = 0
+			cola = append(cola, Pos{o.Y, o.X})
+		}
+	}
+
+	dirs := []Pos{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
+
+	for len(cola) > 0 {
+		p := cola[0]
+		cola = cola[1:]
+		for _, dir := range dirs {
+			ny, nx := p.Y+dir.Y, p.X+dir.X
+			if m.IsWalkable(nx, ny) && distancias[ny][nx] == -1 {
+				distancias[ny][nx] = distancias[p.Y][p.X] + 1
+				cola = append(cola, Pos{ny, nx})
+			}
+		}
+	}
+
+	return distancias
+}
+
+// NearestReachable la celda alcanzable desde el origen que queda mas cerca de la meta,
+// sirve cuando no hay camino hasta la meta
+func (m Maze) NearestReachable(origen, meta *Node) *Node {
+	mejor := origen
+	for y, fila := range m.DistanceField(origen) {
+		for x, d := range fila {
+			if d >= 0 && abs(x-meta.X)+abs(y-meta.Y) < abs(mejor.X-meta.X)+abs(mejor.Y-meta.Y) {
+				mejor = NewNode(x, y)
+			}
+		}
+	}
+	return mejor
+}
+
+// SearchPattern regresa puntos de busqueda alrededor de un origen,
+// uno por cada anillo de distancia, ordenados del mas cercano al mas lejano
+func (m Maze) SearchPattern(origen *Node, radio, paso int) []*Node {
+	distancias := m.DistanceField(origen)
+
+	anillos := make([][]*Node, radio+1)
+	for y, fila := range distancias {
+		for x, d := range fila {
+			if d >= 0 && d <= radio {
+				anillos[d] = append(anillos[d], NewNode(x, y))
+			}
+		}
+	}
+
+	var puntos []*Node
+	for d := paso; d <= radio; d += paso {
+		if len(anillos[d]) == 0 {
+			break // ya no hay celdas mas lejanas
+		}
+		puntos = append(puntos, anillos[d][rand.Intn(len(anillos[d]))])
+	}
+	return puntos
+}
+
+// OpenNeighbors cuenta las celdas transitables arriba, abajo, izquierda y derecha
+func (m Maze) OpenNeighbors(x, y int) int {
+	c := 0
+	for _, d := range []Pos{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+		if m.IsWalkable(x+d.X, y+d.Y) {
+			c++
+		}
+	}
+	return c
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// Cells regresa todas las celdas del mapa con el valor dado
+func (m Maze) Cells(valor int) []*Node {
+	var celdas []*Node
+	for y, fila := range m {
+		for x, v := range fila {
+			if v == valor {
+				celdas = append(celdas, NewNode(x, y))
+			}
+		}
+	}
+	return celdas
+}
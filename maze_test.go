@@ -0,0 +1,50 @@
+package main
+
+import "testing"
+
+// salaConPilar sala de 5x5 libres con un pilar en medio, las orillas son muros
+func salaConPilar() Maze {
+	m := Maze{
+		{1, 1, 1, 1, 1, 1, 1},
+		{1, 0, 0, 0, 0, 0, 1},
+		{1, 0, 0, 0, 0, 0, 1},
+		{1, 0, 0, 1, 0, 0, 1},
+		{1, 0, 0, 0, 0, 0, 1},
+		{1, 0, 0, 0, 0, 0, 1},
+		{1, 1, 1, 1, 1, 1, 1},
+	}
+	m.Set(2, 1, AjolotePointType)
+	m.Set(4, 5, PowerUpSpeed.Cell())
+	return m
+}
+
+func TestLineOfSight(t *testing.T) {
+	casos := []struct {
+		nombre string
+		a, b   *Node
+		ve     bool
+	}{
+		{"misma_celda", NewNode(1, 1), NewNode(1, 1), true},
+		{"fila_libre", NewNode(1, 1), NewNode(5, 1), true},
+		{"columna_libre", NewNode(1, 1), NewNode(1, 5), true},
+		{"pilar_en_la_fila", NewNode(1, 3), NewNode(5, 3), false},
+		{"pilar_en_la_columna", NewNode(3, 1), NewNode(3, 5), false},
+		{"pilar_en_la_diagonal", NewNode(1, 1), NewNode(5, 5), false},
+		{"otra_diagonal", NewNode(1, 5), NewNode(5, 1), false},
+		// bresenham pasa por (2,3), (3,3) y (4,4), choca con el pilar
+		{"inclinada_contra_el_pilar", NewNode(1, 2), NewNode(5, 4), false},
+		// pasa por (3,2) y (4,3), junto al pilar sin tocarlo
+		{"inclinada_junto_al_pilar", NewNode(2, 1), NewNode(5, 4), true},
+		// los ajolotes y los power-ups no tapan la vista
+		{"sobre_un_ajolote", NewNode(1, 1), NewNode(3, 1), true},
+		{"sobre_un_power_up", NewNode(1, 5), NewNode(5, 5), true},
+		{"desde_un_muro", NewNode(0, 1), NewNode(3, 1), false},
+		{"hacia_un_muro", NewNode(1, 1), NewNode(6, 1), false},
+	}
+	m := salaConPilar()
+	for _, caso := range casos {
+		if ve := m.LineOfSight(caso.a, caso.b); ve != caso.ve {
+			t.Errorf("%s: de %v a %v ve %v, se esperaba %v", caso.nombre, *caso.a, *caso.b, ve, caso.ve)
+		}
+	}
+}
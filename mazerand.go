@@ -18,3 +18,15 @@ func Mazerand(mapa Maze) {
 		}
 	}
 }
+
+// RandomWalkable regresa una celda transitable aleatoria del mapa
+func (m Maze) RandomWalkable() *Node {
+	f, c := m.GetShape()
+	for {
+		x := rand.IntN(c)
+		y := rand.IntN(f)
+		if m.IsWalkable(x, y) {
+			return NewNode(x, y)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Menu menu de pausa, desde aqui se reasignan los controles
+type Menu struct {
+	Selected   int       // opcion seleccionada
+	Rebinding  Action    // accion que se esta reasignando
+	PauseStart time.Time // cuando empezo la pausa, para no contarla en el tiempo de partida
+	Message    string
+}
+
+// opciones: continuar, una por cada accion, las dos opciones de movimiento y restaurar
+func (m *Menu) numOptions() int {
+	return len(Actions) + 4
+}
+
+// Pause abre el menu de pausa
+func (j *Game) Pause() {
+	j.State = PausedState
+	j.Menu.Selected = 0
+	j.Menu.Message = ""
+	j.Menu.PauseStart = time.Now()
+}
+
+// Resume cierra el menu, el tiempo en pausa no cuenta para la partida
+func (j *Game) Resume() {
+	j.StartTime = j.StartTime.Add(time.Since(j.Menu.PauseStart))
+	j.State = PlayingState
+}
+
+// UpdateMenu navega el menu de pausa
+func (j *Game) UpdateMenu() {
+	m := j.Menu
+	in := j.Input
+
+	switch {
+	case in.JustPressed(ActionPause):
+		j.Resume()
+	case in.JustPressed(ActionUp):
+		m.Selected = (m.Selected - 1 + m.numOptions()) % m.numOptions()
+	case in.JustPressed(ActionDown):
+		m.Selected = (m.Selected + 1) % m.numOptions()
+	case in.JustPressed(ActionConfirm):
+		switch {
+		case m.Selected == 0:
+			j.Resume()
+		case m.Selected <= len(Actions):
+			m.Rebinding = Actions[m.Selected-1]
+			j.State = RebindState
+		case m.Selected == len(Actions)+1:
+			j.Settings.BufferInput = !j.Settings.BufferInput
+			j.saveSettings()
+		case m.Selected == len(Actions)+2:
+			j.Settings.AutoRun = !j.Settings.AutoRun
+			j.saveSettings()
+		default:
+			j.Settings.Bindings = DefaultBindings()
+			j.Input.Bindings = j.Settings.Bindings
+			j.saveSettings()
+		}
+	}
+}
+
+// UpdateRebind espera la siguiente tecla o boton para asignarlo a la accion seleccionada
+func (j *Game) UpdateRebind() {
+	tecla, boton := j.Input.JustPressedAny()
+	if tecla == nil && boton == nil {
+		return
+	}
+
+	b := j.Settings.Bindings.Actions[j.Menu.Rebinding]
+	if b == nil {
+		b = &Binding{}
+		j.Settings.Bindings.Actions[j.Menu.Rebinding] = b
+	}
+
+	// la tecla o boton reemplaza los anteriores, los ejes se conservan
+	if tecla != nil {
+		b.Keys = []ebiten.Key{*tecla}
+	} else {
+		b.Buttons = []ebiten.StandardGamepadButton{*boton}
+	}
+
+	j.saveSettings()
+	j.State = PausedState
+}
+
+func (j *Game) saveSettings() {
+	if err := j.Settings.Save(SettingsFile); err != nil {
+		log.Println(err)
+		j.Menu.Message = "No se pudo guardar la configuracion"
+		return
+	}
+	j.Menu.Message = "Configuracion guardada"
+}
+
+// DrawMenu dibuja el menu sobre el juego
+func (j *Game) DrawMenu(screen *ebiten.Image) {
+	vector.FillRect(screen, 0, 0, float32(j.Dimensiones.Ancho), float32(j.Dimensiones.Alto), color.RGBA{A: 200}, false)
+
+	lineas := []string{"PAUSA", ""}
+	opciones := []string{"Continuar"}
+	for _, a := range Actions {
+		opciones = append(opciones, fmt.Sprintf("%s: %s", a, j.Settings.Bindings.Actions[a].Describe()))
+	}
+	opciones = append(opciones,
+		"Guardar direccion: "+siNo(j.Settings.BufferInput),
+		"Seguir avanzando: "+siNo(j.Settings.AutoRun),
+		"Restaurar controles",
+	)
+
+	for i, o := range opciones {
+		prefijo := "  "
+		if i == j.Menu.Selected {
+			prefijo = "> "
+		}
+		lineas = append(lineas, prefijo+o)
+	}
+
+	lineas = append(lineas, "")
+	if j.State == RebindState {
+		lineas = append(lineas, fmt.Sprintf("Presiona una tecla o boton para %s", j.Menu.Rebinding))
+	} else if j.Menu.Message != "" {
+		lineas = append(lineas, j.Menu.Message)
+	}
+
+	for i, l := range lineas {
+		fuente := *j.Font.Options
+		fuente.GeoM.Translate(MenuMargin, MenuMargin+float64(i)*FontSize*2.5)
+		text.Draw(screen, l, j.Font.Face, &fuente)
+	}
+}
+
+func siNo(v bool) string {
+	if v {
+		return "si"
+	}
+	return "no"
+}
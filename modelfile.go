@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// InfoEntrenamiento datos de como se entreno un modelo, solo informativos
+type InfoEntrenamiento struct {
+	Fecha             time.Time
+	Muestras          int // registros usados para entrenar
+	Epocas            int
+	MejorEpoca        int
+	Optimizador       string
+	AjusteLR          string
+	LR                float64
+	TamLote           int
+	L2                float64
+	Perdida           string
+	PerdidaFinal      float64
+	PerdidaValidacion float64
+	Prueba            Metricas // metricas en los registros de prueba, cero si no hubo
+}
+
+// ArchivoModelo lo que se escribe en disco, el contenido va aparte para poder verificarlo
+type ArchivoModelo struct {
+	Formato   string // siempre ModelFormatName, los archivos viejos no lo traen
+	Version   int
+	Checksum  string // sha256 del contenido en hexadecimal
+	Contenido []byte // ModelDump codificado con gob
+}
+
+// Estructura auxiliar para guardar en archivo (Gob)
+type ModelDump struct {
+	WData [][]float64
+	WRows []int
+	WCols []int
+	BData [][]float64
+	BRows []int
+	BCols []int
+	// nombre de la activacion de cada capa, los modelos viejos no lo traen y usan sigmoide
+	Activaciones []string
+
+	// desde la version 2
+	Caracteristicas []string
+	Entrada         *Escalador
+	Salida          *Escalador
+	Info            InfoEntrenamiento
+}
+
+// volcar copia la red a la estructura que se codifica
+func volcar(red *Red) ModelDump {
+	dump := ModelDump{
+		Caracteristicas: red.Caracteristicas,
+		Entrada:         red.Entrada,
+		Salida:          red.Salida,
+		Info:            red.Info,
+	}
+
+	for _, w := range red.W {
+		r, c := w.Dims()
+		dump.WRows = append(dump.WRows, r)
+		dump.WCols = append(dump.WCols, c)
+		// Guardamos los datos crudos directos del Dense (row-major), que es lo que espera NewDense al cargar
+		dump.WData = append(dump.WData, w.RawMatrix().Data)
+	}
+	for _, b := range red.B {
+		r, c := b.Dims()
+		dump.BRows = append(dump.BRows, r)
+		dump.BCols = append(dump.BCols, c)
+		dump.BData = append(dump.BData, b.RawMatrix().Data)
+	}
+	for i := range red.W {
+		// una red sin activaciones es de los modelos originales con sigmoide
+		nombre := Sigmoid.Name()
+		if i < len(red.Activaciones) {
+			nombre = red.Activaciones[i].Name()
+		}
+		dump.Activaciones = append(dump.Activaciones, nombre)
+	}
+	return dump
+}
+
+// guardarArchivo codifica valor y lo escribe con el encabezado del formato y su checksum
+func guardarArchivo(nombre, formato string, version int, valor any) error {
+	var contenido bytes.Buffer
+	if err := gob.NewEncoder(&contenido).Encode(valor); err != nil {
+		return fmt.Errorf("error al codificar el modelo: %v", err)
+	}
+	suma := sha256.Sum256(contenido.Bytes())
+
+	file, err := os.Create(nombre + ".gob")
+	if err != nil {
+		return fmt.Errorf("error creando archivo: %v", err)
+	}
+	defer file.Close()
+
+	err = gob.NewEncoder(file).Encode(ArchivoModelo{
+		Formato:   formato,
+		Version:   version,
+		Checksum:  hex.EncodeToString(suma[:]),
+		Contenido: contenido.Bytes(),
+	})
+	if err != nil {
+		return fmt.Errorf("error al guardar el modelo: %v", err)
+	}
+	return nil
+}
+
+// GuardarRed guarda la red con todo lo necesario para usarla: arquitectura, activaciones,
+// caracteristicas, escaladores y datos del entrenamiento
+func GuardarRed(nombreModelo string, red *Red) error {
+	if err := guardarArchivo(nombreModelo, ModelFormatName, ModelFormatVersion, volcar(red)); err != nil {
+		return err
+	}
+	fmt.Println("El calculo de W y B se guardo en disco: " + nombreModelo + ".gob !!!")
+	return nil
+}
+
+// errSinEncabezado el archivo no trae ArchivoModelo, es un modelo de la version 1
+var errSinEncabezado = errors.New("el archivo no tiene encabezado")
+
+// abrirArchivo revisa el encabezado y el checksum, regresa el contenido y su version
+func abrirArchivo(data []byte, formato string, version int) ([]byte, int, error) {
+	var archivo ArchivoModelo
+	// un archivo viejo no tiene ningun campo de ArchivoModelo y falla al decodificar
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&archivo); err != nil || archivo.Formato == "" {
+		return nil, 0, errSinEncabezado
+	}
+	if archivo.Formato != formato {
+		return nil, 0, fmt.Errorf("se esperaba un modelo %q y el archivo es %q", formato, archivo.Formato)
+	}
+	if archivo.Version > version {
+		return nil, 0, fmt.Errorf("el modelo usa la version %d del formato, esta version del juego solo lee hasta la %d", archivo.Version, version)
+	}
+	suma := sha256.Sum256(archivo.Contenido)
+	if hex.EncodeToString(suma[:]) != archivo.Checksum {
+		return nil, 0, fmt.Errorf("el modelo esta corrupto, el checksum no coincide")
+	}
+	return archivo.Contenido, archivo.Version, nil
+}
+
+// leerDump decodifica el archivo, acepta el formato versionado y el original sin encabezado
+func leerDump(data []byte) (ModelDump, int, error) {
+	var dump ModelDump
+
+	contenido, version, err := abrirArchivo(data, ModelFormatName, ModelFormatVersion)
+	if errors.Is(err, errSinEncabezado) {
+		contenido, version, err = data, 1, nil
+	}
+	if err != nil {
+		return dump, 0, err
+	}
+	if err = gob.NewDecoder(bytes.NewReader(contenido)).Decode(&dump); err != nil {
+		return dump, 0, fmt.Errorf("error al decodificar el modelo: %v", err)
+	}
+	return dump, version, nil
+}
+
+/*
+CargarRed lee el modelo junto con la activacion de cada capa.
+La red elige sus columnas con las caracteristicas que guarda, los modelos de la version 1
+no las guardan y se aceptan tal cual
+*/
+func CargarRed(nombreModelo string) (*Red, error) {
+	// 1. Leer el archivo
+	data, err := os.ReadFile(nombreModelo + ".gob")
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo abrir el archivo: %v", err)
+	}
+
+	// 2. Decodificar la estructura ModelDump
+	dump, version, err := leerDump(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(dump.WRows) == 0 {
+		return nil, fmt.Errorf("el modelo no tiene capas")
+	}
+	if dump.Caracteristicas != nil && len(dump.Caracteristicas) != dump.WRows[0] {
+		return nil, fmt.Errorf("el modelo tiene %d caracteristicas pero su primera capa recibe %d", len(dump.Caracteristicas), dump.WRows[0])
+	}
+	if err = validarCaracteristicas(dump.Caracteristicas); err != nil {
+		return nil, err
+	}
+
+	// 3. Reconstruir los Pesos (W)
+	W := make([]*mat.Dense, len(dump.WRows))
+	for i := 0; i < len(dump.WRows); i++ {
+		// NewDense toma (filas, columnas, data)
+		// Gonum copiará el slice dump.WData[i] para crear la matriz
+		W[i] = mat.NewDense(dump.WRows[i], dump.WCols[i], dump.WData[i])
+	}
+
+	// 4. Reconstruir los Bias (B)
+	B := make([]*mat.Dense, len(dump.BRows))
+	for i := 0; i < len(dump.BRows); i++ {
+		B[i] = mat.NewDense(dump.BRows[i], dump.BCols[i], dump.BData[i])
+	}
+
+	// los modelos sin activaciones son los originales, sigmoide en todas las capas
+	red := &Red{
+		W:               W,
+		B:               B,
+		Caracteristicas: dump.Caracteristicas,
+		Entrada:         dump.Entrada,
+		Salida:          dump.Salida,
+		Info:            dump.Info,
+	}
+	for i := range W {
+		nombre := Sigmoid.Name()
+		if i < len(dump.Activaciones) {
+			nombre = dump.Activaciones[i]
+		}
+		a, err := ActivationByName(nombre)
+		if err != nil {
+			return nil, err
+		}
+		red.Activaciones = append(red.Activaciones, a)
+	}
+
+	fmt.Printf("Modelo '%s.gob' (formato v%d) cargado correctamente.\n", nombreModelo, version)
+	return red, nil
+}
@@ -0,0 +1,386 @@
+package main
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+/*
+ModeloJSON formato portable del modelo, para revisarlo en un diff o cargarlo desde Python.
+Cada capa calcula a = activacion(x @ pesos + bias), con x de forma (muestras, entradas),
+asi que con numpy basta:
+
+	x = (x - entrada["desplazamiento"]) / entrada["escala"]
+	for capa in capas:
+	    x = f[capa["activacion"]](x @ np.array(capa["pesos"]) + np.array(capa["bias"]))
+	y = x * salida["escala"] + salida["desplazamiento"]
+
+entrada y salida pueden no venir, en ese caso no se escala
+*/
+type ModeloJSON struct {
+	Formato         string             `json:"formato"` // siempre ModelFormatName
+	Version         int                `json:"version"`
+	Caracteristicas []string           `json:"caracteristicas,omitempty"` // nombre de cada columna de x, en orden
+	Entrada         *EscaladorJSON     `json:"entrada,omitempty"`
+	Salida          *EscaladorJSON     `json:"salida,omitempty"`
+	Capas           []CapaJSON         `json:"capas"`
+	Entrenamiento   *EntrenamientoJSON `json:"entrenamiento,omitempty"`
+}
+
+// EscaladorJSON normalizar es (x - desplazamiento) / escala, desnormalizar es y * escala + desplazamiento
+type EscaladorJSON struct {
+	Tipo           string    `json:"tipo"` // zscore o minmax, solo informativo
+	Desplazamiento []float64 `json:"desplazamiento"`
+	Escala         []float64 `json:"escala"`
+}
+
+// CapaJSON pesos tiene forma (entradas, neuronas) y bias (neuronas)
+type CapaJSON struct {
+	Entradas   int         `json:"entradas"`
+	Neuronas   int         `json:"neuronas"`
+	Activacion string      `json:"activacion"` // sigmoide, relu, leaky_relu:<alpha>, tanh, lineal o softmax
+	Pesos      [][]float64 `json:"pesos"`
+	Bias       []float64   `json:"bias"`
+}
+
+// EntrenamientoJSON mismos datos que InfoEntrenamiento, R² es null si no se pudo calcular
+type EntrenamientoJSON struct {
+	Fecha             time.Time `json:"fecha"`
+	Muestras          int       `json:"muestras"`
+	Epocas            int       `json:"epocas"`
+	MejorEpoca        int       `json:"mejor_epoca"`
+	Optimizador       string    `json:"optimizador"`
+	AjusteLR          string    `json:"ajuste_lr"`
+	LR                float64   `json:"lr"`
+	TamLote           int       `json:"tam_lote"`
+	L2                float64   `json:"l2"`
+	Perdida           string    `json:"perdida"`
+	PerdidaFinal      float64   `json:"perdida_final"`
+	PerdidaValidacion float64   `json:"perdida_validacion"`
+	PruebaMAE         float64   `json:"prueba_mae"`
+	PruebaRMSE        float64   `json:"prueba_rmse"`
+	PruebaR2          *float64  `json:"prueba_r2"`
+}
+
+func escaladorAJSON(e *Escalador) *EscaladorJSON {
+	if e == nil {
+		return nil
+	}
+	return &EscaladorJSON{Tipo: e.Tipo, Desplazamiento: e.Desplazamiento, Escala: e.Escala}
+}
+
+func escaladorDeJSON(e *EscaladorJSON) *Escalador {
+	if e == nil {
+		return nil
+	}
+	return &Escalador{Tipo: e.Tipo, Desplazamiento: e.Desplazamiento, Escala: e.Escala}
+}
+
+// RedAJSON convierte la red al formato portable
+func RedAJSON(red *Red) ModeloJSON {
+	m := ModeloJSON{
+		Formato:         ModelFormatName,
+		Version:         ModelFormatVersion,
+		Caracteristicas: red.Caracteristicas,
+		Entrada:         escaladorAJSON(red.Entrada),
+		Salida:          escaladorAJSON(red.Salida),
+	}
+	for i, w := range red.W {
+		r, c := w.Dims()
+		capa := CapaJSON{
+			Entradas:   r,
+			Neuronas:   c,
+			Activacion: red.Activaciones[i].Name(),
+			Bias:       mat.Row(nil, 0, red.B[i]),
+		}
+		for f := 0; f < r; f++ {
+			capa.Pesos = append(capa.Pesos, mat.Row(nil, f, w))
+		}
+		m.Capas = append(m.Capas, capa)
+	}
+
+	// los modelos sin entrenamiento registrado (version 1) no lo llevan
+	if info := red.Info; !info.Fecha.IsZero() {
+		m.Entrenamiento = &EntrenamientoJSON{
+			Fecha:             info.Fecha,
+			Muestras:          info.Muestras,
+			Epocas:            info.Epocas,
+			MejorEpoca:        info.MejorEpoca,
+			Optimizador:       info.Optimizador,
+			AjusteLR:          info.AjusteLR,
+			LR:                info.LR,
+			TamLote:           info.TamLote,
+			L2:                info.L2,
+			Perdida:           info.Perdida,
+			PerdidaFinal:      info.PerdidaFinal,
+			PerdidaValidacion: info.PerdidaValidacion,
+			PruebaMAE:         info.Prueba.MAE,
+			PruebaRMSE:        info.Prueba.RMSE,
+		}
+		// JSON no tiene NaN
+		if !math.IsNaN(info.Prueba.R2) {
+			r2 := info.Prueba.R2
+			m.Entrenamiento.PruebaR2 = &r2
+		}
+	}
+	return m
+}
+
+// RedDeJSON reconstruye la red y revisa que las dimensiones de las capas encajen
+func RedDeJSON(m ModeloJSON) (*Red, error) {
+	if m.Formato != ModelFormatName {
+		return nil, fmt.Errorf("el archivo no es un modelo: formato %q", m.Formato)
+	}
+	if m.Version > ModelFormatVersion {
+		return nil, fmt.Errorf("el modelo usa la version %d del formato, esta version del juego solo lee hasta la %d", m.Version, ModelFormatVersion)
+	}
+	if len(m.Capas) == 0 {
+		return nil, fmt.Errorf("el modelo no tiene capas")
+	}
+	if m.Caracteristicas != nil && len(m.Caracteristicas) != m.Capas[0].Entradas {
+		return nil, fmt.Errorf("el modelo tiene %d caracteristicas pero su primera capa recibe %d", len(m.Caracteristicas), m.Capas[0].Entradas)
+	}
+	if err := validarCaracteristicas(m.Caracteristicas); err != nil {
+		return nil, err
+	}
+
+	red := &Red{
+		Caracteristicas: m.Caracteristicas,
+		Entrada:         escaladorDeJSON(m.Entrada),
+		Salida:          escaladorDeJSON(m.Salida),
+	}
+	for i, capa := range m.Capas {
+		if i > 0 && capa.Entradas != m.Capas[i-1].Neuronas {
+			return nil, fmt.Errorf("la capa %d recibe %d entradas pero la anterior tiene %d neuronas", i+1, capa.Entradas, m.Capas[i-1].Neuronas)
+		}
+		if len(capa.Pesos) != capa.Entradas || len(capa.Bias) != capa.Neuronas {
+			return nil, fmt.Errorf("la capa %d no tiene la forma (%d, %d)", i+1, capa.Entradas, capa.Neuronas)
+		}
+		w := mat.NewDense(capa.Entradas, capa.Neuronas, nil)
+		for f, fila := range capa.Pesos {
+			if len(fila) != capa.Neuronas {
+				return nil, fmt.Errorf("la capa %d no tiene la forma (%d, %d)", i+1, capa.Entradas, capa.Neuronas)
+			}
+			w.SetRow(f, fila)
+		}
+		a, err := ActivationByName(capa.Activacion)
+		if err != nil {
+			return nil, err
+		}
+		red.W = append(red.W, w)
+		red.B = append(red.B, mat.NewDense(1, capa.Neuronas, append([]float64{}, capa.Bias...)))
+		red.Activaciones = append(red.Activaciones, a)
+	}
+
+	if e := m.Entrenamiento; e != nil {
+		red.Info = InfoEntrenamiento{
+			Fecha:             e.Fecha,
+			Muestras:          e.Muestras,
+			Epocas:            e.Epocas,
+			MejorEpoca:        e.MejorEpoca,
+			Optimizador:       e.Optimizador,
+			AjusteLR:          e.AjusteLR,
+			LR:                e.LR,
+			TamLote:           e.TamLote,
+			L2:                e.L2,
+			Perdida:           e.Perdida,
+			PerdidaFinal:      e.PerdidaFinal,
+			PerdidaValidacion: e.PerdidaValidacion,
+			Prueba:            Metricas{MAE: e.PruebaMAE, RMSE: e.PruebaRMSE, R2: math.NaN()},
+		}
+		if e.PruebaR2 != nil {
+			red.Info.Prueba.R2 = *e.PruebaR2
+		}
+	}
+	return red, nil
+}
+
+// ExportarJSON guarda la red en un archivo JSON indentado, facil de revisar en un diff
+func ExportarJSON(red *Red, archivo string) error {
+	data, err := json.MarshalIndent(RedAJSON(red), "", "  ")
+	if err != nil {
+		return fmt.Errorf("error al codificar el modelo: %v", err)
+	}
+	if err = os.WriteFile(archivo, data, 0o644); err != nil {
+		return fmt.Errorf("no se pudo guardar el modelo: %v", err)
+	}
+	return nil
+}
+
+// ImportarJSON lee una red exportada con ExportarJSON
+func ImportarJSON(archivo string) (*Red, error) {
+	data, err := os.ReadFile(archivo)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo abrir el archivo: %v", err)
+	}
+	var m ModeloJSON
+	if err = json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("error al decodificar el modelo: %v", err)
+	}
+	return RedDeJSON(m)
+}
+
+// cargarCualquiera lee un modelo .json o .gob, el .gob puede venir sin extension
+func cargarCualquiera(archivo string) (*Red, error) {
+	if strings.HasSuffix(archivo, ".json") {
+		return ImportarJSON(archivo)
+	}
+	return CargarRed(strings.TrimSuffix(archivo, ".gob"))
+}
+
+// estadisticas resumen de los valores de una matriz para inspeccionar un modelo
+func estadisticas(m *mat.Dense) string {
+	r, c := m.Dims()
+	n := float64(r * c)
+	minimo, maximo := math.Inf(1), math.Inf(-1)
+	suma, cuadrados := 0.0, 0.0
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			v := m.At(i, j)
+			minimo = math.Min(minimo, v)
+			maximo = math.Max(maximo, v)
+			suma += v
+			cuadrados += v * v
+		}
+	}
+	media := suma / n
+	desviacion := math.Sqrt(math.Max(cuadrados/n-media*media, 0))
+	return fmt.Sprintf("media %.4f, desv %.4f, min %.4f, max %.4f", media, desviacion, minimo, maximo)
+}
+
+// formatoDeArchivo formato del encabezado de un .gob, vacio si es un modelo de la version 1
+func formatoDeArchivo(nombre string) (string, error) {
+	data, err := os.ReadFile(nombre + ".gob")
+	if err != nil {
+		return "", fmt.Errorf("no se pudo abrir el archivo: %v", err)
+	}
+	var archivo ArchivoModelo
+	if gob.NewDecoder(bytes.NewReader(data)).Decode(&archivo) != nil {
+		return "", nil
+	}
+	return archivo.Formato, nil
+}
+
+// inspeccionarRidge imprime las betas y el escalado de un modelo ridge
+func inspeccionarRidge(r *RidgeModel) {
+	fmt.Printf("Ridge (%s), lambda %g", r.Solver, r.Lambda)
+	if r.Epocas > 0 {
+		fmt.Printf(", %d epocas", r.Epocas)
+	}
+	fmt.Printf("\nb0: %f\n", r.B0)
+	for j, nombre := range r.Caracteristicas {
+		fmt.Printf("  %s: beta %f", nombre, r.Betas[j])
+		if r.Escalado {
+			fmt.Printf(", media %f, desv %f", r.MediasX[j], r.DesviacionesX[j])
+		}
+		fmt.Println()
+	}
+	if r.Escalado {
+		fmt.Printf("Salida: media %f, desv %f\n", r.MediaY, r.DesviacionY)
+	}
+}
+
+// InspeccionarModelo imprime las capas, sus estadisticas y como se entreno el modelo
+func InspeccionarModelo(archivo string) error {
+	if !strings.HasSuffix(archivo, ".json") {
+		nombre := strings.TrimSuffix(archivo, ".gob")
+		formato, err := formatoDeArchivo(nombre)
+		if err != nil {
+			return err
+		}
+		if formato == RidgeFormatName {
+			r, err := CargarRidge(nombre)
+			if err != nil {
+				return err
+			}
+			inspeccionarRidge(r)
+			return nil
+		}
+	}
+
+	red, err := cargarCualquiera(archivo)
+	if err != nil {
+		return err
+	}
+
+	if red.Caracteristicas != nil {
+		fmt.Printf("Caracteristicas: %s\n", strings.Join(red.Caracteristicas, ", "))
+	}
+	if red.Entrada != nil {
+		fmt.Printf("Entrada: %s, desplazamiento %v, escala %v\n", red.Entrada.Tipo, red.Entrada.Desplazamiento, red.Entrada.Escala)
+	}
+
+	total := 0
+	for i, w := range red.W {
+		r, c := w.Dims()
+		parametros := r*c + c
+		total += parametros
+		fmt.Printf("Capa %d: %d -> %d, %s, %d parametros\n", i+1, r, c, red.Activaciones[i].Name(), parametros)
+		fmt.Printf("  pesos: %s\n", estadisticas(w))
+		fmt.Printf("  bias:  %s\n", estadisticas(red.B[i]))
+	}
+	fmt.Printf("Total de parametros: %d\n", total)
+
+	if red.Salida != nil {
+		fmt.Printf("Salida: %s, desplazamiento %v, escala %v\n", red.Salida.Tipo, red.Salida.Desplazamiento, red.Salida.Escala)
+	}
+	if info := red.Info; !info.Fecha.IsZero() {
+		fmt.Printf("Entrenado el %s con %d muestras, %d epocas (mejor %d)\n", info.Fecha.Format(time.DateTime), info.Muestras, info.Epocas, info.MejorEpoca)
+		fmt.Printf("  %s, tasa %g (%s), lote %d, l2 %g, perdida %s final %f\n", info.Optimizador, info.LR, info.AjusteLR, info.TamLote, info.L2, info.Perdida, info.PerdidaFinal)
+		if info.Prueba.RMSE > 0 {
+			fmt.Printf("  prueba: %s\n", info.Prueba)
+		}
+	}
+	return nil
+}
+
+/*
+RunModelCommand subcomandos para manejar modelos guardados:
+
+	model inspect <modelo[.gob]|modelo.json>
+	model export <modelo[.gob]> <modelo.json>
+	model import <modelo.json> <modelo[.gob]>
+*/
+func RunModelCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("uso: model inspect|export|import")
+	}
+	switch args[0] {
+	case "inspect":
+		if len(args) != 2 {
+			return fmt.Errorf("uso: model inspect <modelo>")
+		}
+		return InspeccionarModelo(args[1])
+	case "export":
+		if len(args) != 3 {
+			return fmt.Errorf("uso: model export <modelo.gob> <modelo.json>")
+		}
+		red, err := cargarCualquiera(args[1])
+		if err != nil {
+			return err
+		}
+		if err = ExportarJSON(red, args[2]); err != nil {
+			return err
+		}
+		fmt.Printf("Modelo exportado a %s\n", args[2])
+		return nil
+	case "import":
+		if len(args) != 3 {
+			return fmt.Errorf("uso: model import <modelo.json> <modelo.gob>")
+		}
+		red, err := ImportarJSON(args[1])
+		if err != nil {
+			return err
+		}
+		return GuardarRed(strings.TrimSuffix(args[2], ".gob"), red)
+	}
+	return fmt.Errorf("subcomando desconocido: model %s", args[0])
+}
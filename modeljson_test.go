@@ -0,0 +1,140 @@
+package main
+
+import (
+	"math"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+// redDePrueba red con todo lo que se guarda de un modelo: una activacion distinta por capa,
+// caracteristicas, escaladores y datos del entrenamiento
+func redDePrueba(r2 float64) *Red {
+	red := NuevaRed(2, []Capa{
+		{Neuronas: 3, Activacion: LeakyReLU(0.05)},
+		{Neuronas: 2, Activacion: Tanh},
+		{Neuronas: 1, Activacion: Linear},
+	})
+	red.Caracteristicas = []string{"score", "time"}
+	red.Entrada = ZScore([]float64{3000, 60}, []float64{400, 20})
+	red.Salida = MinMax([]float64{EnemyElapseMin}, []float64{EnemyElapseMax})
+	red.Info = InfoEntrenamiento{
+		Fecha:        time.Date(2026, 10, 1, 12, 30, 0, 0, time.UTC),
+		Muestras:     40,
+		Epocas:       120,
+		MejorEpoca:   97,
+		Optimizador:  "adam",
+		AjusteLR:     "coseno",
+		LR:           0.05,
+		TamLote:      MLPBatchSize,
+		Perdida:      "mse",
+		PerdidaFinal: 0.125,
+		Prueba:       Metricas{MAE: 2.5, RMSE: 3.25, R2: r2},
+	}
+	return red
+}
+
+// compararRedes falla si algo de lo que se guarda cambio entre las dos redes
+func compararRedes(t *testing.T, obtenida, esperada *Red) {
+	t.Helper()
+	if len(obtenida.W) != len(esperada.W) {
+		t.Fatalf("%d capas, se esperaban %d", len(obtenida.W), len(esperada.W))
+	}
+	for i := range esperada.W {
+		compararMatrices(t, "W", obtenida.W[i], esperada.W[i], 0)
+		compararMatrices(t, "B", obtenida.B[i], esperada.B[i], 0)
+		if a, e := obtenida.Activaciones[i].Name(), esperada.Activaciones[i].Name(); a != e {
+			t.Errorf("la capa %d tiene %s, se esperaba %s", i, a, e)
+		}
+	}
+	if !slices.Equal(obtenida.Caracteristicas, esperada.Caracteristicas) {
+		t.Errorf("caracteristicas %v, se esperaban %v", obtenida.Caracteristicas, esperada.Caracteristicas)
+	}
+	if !reflect.DeepEqual(obtenida.Entrada, esperada.Entrada) || !reflect.DeepEqual(obtenida.Salida, esperada.Salida) {
+		t.Errorf("escaladores %+v %+v, se esperaban %+v %+v", obtenida.Entrada, obtenida.Salida, esperada.Entrada, esperada.Salida)
+	}
+
+	// NaN no es igual a si mismo y la fecha puede cambiar de representacion, se revisan aparte
+	a, e := obtenida.Info, esperada.Info
+	if math.IsNaN(e.Prueba.R2) != math.IsNaN(a.Prueba.R2) {
+		t.Errorf("R² %g, se esperaba %g", a.Prueba.R2, e.Prueba.R2)
+	}
+	if !a.Fecha.Equal(e.Fecha) {
+		t.Errorf("fecha %v, se esperaba %v", a.Fecha, e.Fecha)
+	}
+	a.Prueba.R2, e.Prueba.R2 = 0, 0
+	a.Fecha, e.Fecha = time.Time{}, time.Time{}
+	if a != e {
+		t.Errorf("entrenamiento %+v, se esperaba %+v", a, e)
+	}
+}
+
+// gob -> JSON -> gob, tanto con las funciones como con los subcomandos model export e import
+func TestModeloJSONIdaYVuelta(t *testing.T) {
+	for _, r2 := range []float64{math.NaN(), 0.42} {
+		dir := t.TempDir()
+		original := redDePrueba(r2)
+
+		if err := GuardarRed(filepath.Join(dir, "red"), original); err != nil {
+			t.Fatal(err)
+		}
+		deGob, err := CargarRed(filepath.Join(dir, "red"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		compararRedes(t, deGob, original)
+
+		if err = ExportarJSON(deGob, filepath.Join(dir, "red.json")); err != nil {
+			t.Fatal(err)
+		}
+		deJSON, err := ImportarJSON(filepath.Join(dir, "red.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		compararRedes(t, deJSON, original)
+
+		comandos := [][]string{
+			{"export", filepath.Join(dir, "red.gob"), filepath.Join(dir, "comando.json")},
+			{"import", filepath.Join(dir, "comando.json"), filepath.Join(dir, "otra.gob")},
+			{"inspect", filepath.Join(dir, "otra.gob")},
+			{"inspect", filepath.Join(dir, "comando.json")},
+		}
+		for _, args := range comandos {
+			if err = RunModelCommand(args); err != nil {
+				t.Fatalf("model %v: %v", args, err)
+			}
+		}
+		final, err := CargarRed(filepath.Join(dir, "otra"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		compararRedes(t, final, original)
+	}
+}
+
+func TestModeloJSONFormaInvalida(t *testing.T) {
+	casos := []struct {
+		nombre  string
+		cambiar func(m *ModeloJSON)
+	}{
+		{"capas_no_encajan", func(m *ModeloJSON) { m.Capas[1].Entradas = 4 }},
+		{"faltan_filas", func(m *ModeloJSON) { m.Capas[0].Pesos = m.Capas[0].Pesos[1:] }},
+		{"fila_corta", func(m *ModeloJSON) { m.Capas[1].Pesos[0] = m.Capas[1].Pesos[0][1:] }},
+		{"bias_largo", func(m *ModeloJSON) { m.Capas[2].Bias = append(m.Capas[2].Bias, 1) }},
+		{"caracteristicas", func(m *ModeloJSON) { m.Caracteristicas = m.Caracteristicas[:1] }},
+		{"activacion", func(m *ModeloJSON) { m.Capas[0].Activacion = "no_existe" }},
+		{"sin_capas", func(m *ModeloJSON) { m.Capas = nil }},
+	}
+	for _, caso := range casos {
+		m := RedAJSON(redDePrueba(math.NaN()))
+		caso.cambiar(&m)
+		if _, err := RedDeJSON(m); err == nil {
+			t.Errorf("%s: se esperaba error", caso.nombre)
+		}
+	}
+	if _, err := RedDeJSON(RedAJSON(redDePrueba(math.NaN()))); err != nil {
+		t.Errorf("el modelo sin cambios: %v", err)
+	}
+}
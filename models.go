@@ -7,11 +7,58 @@ import (
 
 type GameScore struct {
 	gorm.Model
-	Velocity int     `json:"velocity"`
-	Score    uint    `json:"score"`
-	Time     float64 `json:"time"`
+	Velocity  int            `json:"velocity"` // promedio de los lapsos finales de los enemigos
+	Score     uint           `json:"score"`
+	Time      float64        `json:"time"`
+	LivesUsed int            `json:"lives_used"`                    // veces que atraparon al jugador
+	Bot       bool           `json:"bot" gorm:"default:false"`      // la jugo el bot, no una persona
+	Truncada  bool           `json:"truncada" gorm:"default:false"` // se corto antes de terminar, el bot se quedo dando vueltas
+	Enemies   []EnemyScore   `json:"enemies"`
+	PowerUps  []PowerUpUsage `json:"power_ups"`
+	// con dos jugadores cada uno tiene su registro, comparten el MatchID
+	MatchID     string `json:"match_id" gorm:"index"`
+	PlayerIndex int    `json:"player_index"`
+	Role        string `json:"role"` // jugador o perro
+	Mode        string `json:"mode"` // solo, coop o versus
+	// telemetria del jugador, las partidas de antes y las del perro no la tienen
+	Telemetry *MatchTelemetry `json:"telemetry,omitempty"`
+	Deaths    []DeathEvent    `json:"deaths"`
+}
+
+// EnemyScore guarda la curva de velocidad de cada enemigo en una partida
+type EnemyScore struct {
+	gorm.Model
+	GameScoreID     uint   `json:"game_score_id"`
+	Index           int    `json:"index"`
+	Archetype       string `json:"archetype"`
+	Behavior        string `json:"behavior"`
+	InitialElapse   int    `json:"initial_elapse"`
+	FinalElapse     int    `json:"final_elapse"`
+	ElapseDecrement int    `json:"elapse_decrement"`
+	MinElapse       int    `json:"min_elapse"`
+}
+
+// PowerUpUsage registra cada power-up que recoge el jugador en una partida
+type PowerUpUsage struct {
+	gorm.Model
+	GameScoreID uint    `json:"game_score_id"`
+	Kind        string  `json:"kind"`
+	At          float64 `json:"at"`       // segundos desde que empezo la partida
+	Duration    float64 `json:"duration"` // segundos que dura el efecto
+	Score       uint    `json:"score"`    // puntaje al recogerlo
+}
+
+// RunnerScores solo los registros de jugadores que recorren el laberinto,
+// el puntaje del perro en versus no sirve para predecir la velocidad
+func RunnerScores(db *gorm.DB) *gorm.DB {
+	return db.Where("role IS NULL OR role <> ?", RoleDog)
 }
 
 func OpenDB() (*gorm.DB, error) {
 	return gorm.Open(sqlite.Open(DbName), &gorm.Config{})
 }
+
+// MigrateDB crea o actualiza las tablas de las partidas
+func MigrateDB(db *gorm.DB) error {
+	return db.AutoMigrate(&GameScore{}, &EnemyScore{}, &PowerUpUsage{}, &MatchTelemetry{}, &DeathEvent{})
+}
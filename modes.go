@@ -0,0 +1,98 @@
+package main
+
+// EnemyMode indica el modo global en el que se encuentran los enemigos
+type EnemyMode int
+
+const (
+	ChaseMode      EnemyMode = iota // persiguen al jugador segun su comportamiento
+	ScatterMode                     // se dispersan hacia su esquina
+	FrightenedMode                  // huyen del jugador y pueden ser comidos
+)
+
+func (m EnemyMode) String() string {
+	switch m {
+	case ChaseMode:
+		return "persecucion"
+	case ScatterMode:
+		return "dispersion"
+	case FrightenedMode:
+		return "asustado"
+	}
+	return "desconocido"
+}
+
+// ModePhase es un tramo de la linea de tiempo de modos
+type ModePhase struct {
+	Mode     EnemyMode
+	Duration int // duracion en ticks, 0 indica que dura indefinidamente
+}
+
+// DefaultModeTimeline alterna dispersion y persecucion como en los fantasmas clasicos
+var DefaultModeTimeline = []ModePhase{
+	{Mode: ScatterMode, Duration: 7 * TPS},
+	{Mode: ChaseMode, Duration: 20 * TPS},
+	{Mode: ScatterMode, Duration: 7 * TPS},
+	{Mode: ChaseMode, Duration: 20 * TPS},
+	{Mode: ScatterMode, Duration: 5 * TPS},
+	{Mode: ChaseMode, Duration: 20 * TPS},
+	{Mode: ScatterMode, Duration: 5 * TPS},
+	{Mode: ChaseMode},
+}
+
+// ModeScheduler lleva el control de la linea de tiempo de modos que comparten todos los enemigos
+type ModeScheduler struct {
+	Timeline        []ModePhase
+	Phase           int // tramo actual de la linea de tiempo
+	TickCounter     int // ticks transcurridos en el tramo actual
+	FrightenedTicks int // ticks restantes del modo asustado
+}
+
+func NewModeScheduler(timeline []ModePhase) *ModeScheduler {
+	return &ModeScheduler{
+		Timeline: timeline,
+	}
+}
+
+// Tick avanza la linea de tiempo, mientras estan asustados la linea de tiempo se pausa
+func (s *ModeScheduler) Tick() {
+	if s.FrightenedTicks > 0 {
+		s.FrightenedTicks--
+		return
+	}
+
+	if len(s.Timeline) == 0 {
+		return
+	}
+
+	fase := s.Timeline[s.Phase]
+	if fase.Duration == 0 {
+		return // el ultimo tramo es indefinido
+	}
+
+	s.TickCounter++
+	if s.TickCounter >= fase.Duration && s.Phase < len(s.Timeline)-1 {
+		s.TickCounter = 0
+		s.Phase++
+	}
+}
+
+// Mode regresa el modo actual, el modo asustado tiene prioridad sobre la linea de tiempo
+func (s *ModeScheduler) Mode() EnemyMode {
+	if s.FrightenedTicks > 0 {
+		return FrightenedMode
+	}
+	return s.BaseMode()
+}
+
+// BaseMode regresa el modo de la linea de tiempo sin considerar el modo asustado
+func (s *ModeScheduler) BaseMode() EnemyMode {
+	if len(s.Timeline) == 0 {
+		return ChaseMode
+	}
+	return s.Timeline[s.Phase].Mode
+}
+
+// Frighten asusta a todos los enemigos durante los ticks indicados
+func (s *ModeScheduler) Frighten(ticks int) {
+	s.FrightenedTicks = ticks
+}
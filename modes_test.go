@@ -0,0 +1,65 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// letras modo de cada tick: d dispersion, p persecucion, a asustado
+func letras(modos []EnemyMode) string {
+	var b strings.Builder
+	for _, m := range modos {
+		b.WriteByte("pda"[m])
+	}
+	return b.String()
+}
+
+func TestModeScheduler(t *testing.T) {
+	timeline := []ModePhase{
+		{Mode: ScatterMode, Duration: 3},
+		{Mode: ChaseMode, Duration: 2},
+		{Mode: ScatterMode, Duration: 1},
+		{Mode: ChaseMode},
+	}
+	casos := []struct {
+		nombre   string
+		susto    map[int]int // tick en que se asusta -> ticks de susto
+		esperado string      // modo antes de cada tick
+	}{
+		{"linea_de_tiempo", nil, "dddppdpppp"},
+		// el susto pausa la linea de tiempo, al terminar sigue en el mismo punto de la fase
+		{"susto_en_persecucion", map[int]int{4: 3}, "dddpaaapdppp"},
+		{"susto_en_dispersion", map[int]int{1: 2}, "daaddppdpp"},
+		// asustarlos otra vez reinicia la duracion del susto
+		{"susto_renovado", map[int]int{0: 2, 1: 2}, "aaaddd"},
+		{"susto_en_la_fase_indefinida", map[int]int{8: 1}, "dddppdppapp"},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nombre, func(t *testing.T) {
+			s := NewModeScheduler(timeline)
+			var modos []EnemyMode
+			for tick := 0; len(modos) < len(caso.esperado); tick++ {
+				if ticks, ok := caso.susto[tick]; ok {
+					s.Frighten(ticks)
+				}
+				modos = append(modos, s.Mode())
+				s.Tick()
+			}
+			if obtenido := letras(modos); obtenido != caso.esperado {
+				t.Errorf("modos %s, se esperaba %s", obtenido, caso.esperado)
+			}
+		})
+	}
+}
+
+func TestModeSchedulerBaseMode(t *testing.T) {
+	s := NewModeScheduler(DefaultModeTimeline)
+	s.Frighten(10)
+	if s.Mode() != FrightenedMode || s.BaseMode() != ScatterMode {
+		t.Errorf("asustados: modo %v y base %v", s.Mode(), s.BaseMode())
+	}
+	// sin linea de tiempo siempre persiguen
+	if m := NewModeScheduler(nil).Mode(); m != ChaseMode {
+		t.Errorf("sin linea de tiempo el modo es %v", m)
+	}
+}
@@ -1,11 +1,8 @@
 package main
 
 import (
-	"encoding/gob"
-	"fmt"
 	"math"
 	"math/rand"
-	"os"
 	"time"
 
 	"gonum.org/v1/gonum/mat"
@@ -33,79 +30,258 @@ func d_sigmoide_z(z float64) float64 {
 	return s * (1.0 - s)
 }
 
-// applySigmoide aplica la sigmoide a cada elemento de una matriz.
-// Numpy lo hace automáticamente (vectorización), en Gonum usamos Apply.
-func applySigmoide(m mat.Matrix) *mat.Dense {
-	r, c := m.Dims()
+// ==========================================
+// LÓGICA PRINCIPAL
+// ==========================================
+
+// Capa describe una capa de la red: cuantas neuronas tiene y que activacion usa
+type Capa struct {
+	Neuronas   int
+	Activacion Activation
+}
+
+// Red perceptron multicapa, cada capa tiene su propia activacion
+type Red struct {
+	W            []*mat.Dense // almacena los pesos
+	B            []*mat.Dense // almacena las bias, como matrices fila (1, cols)
+	Activaciones []Activation // una por capa
+
+	// lo que hace falta para usar el modelo sin conocer como se entreno
+	Caracteristicas []string   // nombre de cada columna de entrada, en orden
+	Entrada         *Escalador // nil si la red recibe los datos tal cual
+	Salida          *Escalador // nil si la salida ya esta en su escala
+	Info            InfoEntrenamiento
+}
+
+// Escalador transformacion afin por columna, normaliza entradas o regresa salidas a su escala
+type Escalador struct {
+	Tipo           string    // "zscore" o "minmax"
+	Desplazamiento []float64 // media o minimo de cada columna
+	Escala         []float64 // desviacion o rango de cada columna
+}
+
+func ZScore(media, desviacion []float64) *Escalador {
+	return &Escalador{Tipo: "zscore", Desplazamiento: media, Escala: desviacion}
+}
+
+func MinMax(minimo, maximo []float64) *Escalador {
+	rango := make([]float64, len(minimo))
+	for j := range rango {
+		rango[j] = maximo[j] - minimo[j]
+	}
+	return &Escalador{Tipo: "minmax", Desplazamiento: minimo, Escala: rango}
+}
+
+// Normalizar (x - desplazamiento) / escala
+func (e *Escalador) Normalizar(X *mat.Dense) *mat.Dense {
+	r, c := X.Dims()
 	res := mat.NewDense(r, c, nil)
 	res.Apply(func(i, j int, v float64) float64 {
-		return sigmoide(v)
-	}, m)
+		return (v - e.Desplazamiento[j]) / e.Escala[j]
+	}, X)
 	return res
 }
 
-// applyDSigmoide aplica la derivada de la sigmoide a cada elemento.
-func applyDSigmoide(m mat.Matrix) *mat.Dense {
-	r, c := m.Dims()
+// Desnormalizar y * escala + desplazamiento
+func (e *Escalador) Desnormalizar(Y *mat.Dense) *mat.Dense {
+	r, c := Y.Dims()
 	res := mat.NewDense(r, c, nil)
 	res.Apply(func(i, j int, v float64) float64 {
-		return d_sigmoide_z(v)
-	}, m)
+		return v*e.Escala[j] + e.Desplazamiento[j]
+	}, Y)
 	return res
 }
 
-// ==========================================
-// LÓGICA PRINCIPAL
-// ==========================================
-
-/*
-Entrena el modelo segun los patrones dados
-:param X: matriz de caractericas de forma (muestras,caracteristicas)
-:param Yd: matriz de etiquetas
-:param n_in: numero de neuronas entradas
-:param n_out: numero de neuronas de salida
-:param n_layers: lista de numero de neuronas ocultas por capa
-:param lr: learning rate
-:param epoch_max: numero de epocas maximas
-:return: W (pesos), B (bias), ECM_historico
-*/
-func Entrenar(X, Yd *mat.Dense, n_in, n_out int, n_layers []int, lr float64, epoch_max int) ([]*mat.Dense, []*mat.Dense, []float64) {
-
-	// contiene cuantas neuronas tiene cada capa
-	// [GO vs PY]: En Go concatenamos slices manualmente.
-	dimensiones := append([]int{n_in}, n_layers...)
-	dimensiones = append(dimensiones, n_out)
+// ConfigRed arquitectura y parametros de entrenamiento de una red
+type ConfigRed struct {
+	Entradas  int
+	Capas     []Capa // capas ocultas y al final la capa de salida
+	Perdida   Loss
+	LR        float64 // learning rate
+	EpocasMax int
+	TamLote   int // muestras por actualizacion, 1 (o 0) es el descenso estocastico original
+
+	Optimizador Optimizer  // nil usa SGD
+	AjusteLR    LRSchedule // nil deja la tasa fija
+	L2          float64    // decaimiento de los pesos, no se aplica a las bias
+
+	// si hay validacion se regresan los pesos de la epoca con menor perdida de validacion
+	Validacion *Particion
+	Paciencia  int // epocas sin mejorar en validacion antes de detenerse, 0 no se detiene
+}
 
-	numero_dimensiones := len(dimensiones)
-	// numero_capas := len(dimensiones) // No se usa en el original explícitamente
+// Historial perdida promedio por epoca de un entrenamiento
+type Historial struct {
+	Entrenamiento []float64
+	Validacion    []float64 // vacio si se entreno sin validacion
+	MejorEpoca    int       // epoca de los pesos que se regresaron
+}
 
-	// slice de matrices
-	W := make([]*mat.Dense, 0) // almacena los pesos
-	B := make([]*mat.Dense, 0) // almacena las bias
+// sigmoides la configuracion original: sigmoide en todas las capas
+func sigmoides(n_layers []int, n_out int) []Capa {
+	capas := make([]Capa, 0, len(n_layers)+1)
+	for _, n := range n_layers {
+		capas = append(capas, Capa{Neuronas: n, Activacion: Sigmoid})
+	}
+	return append(capas, Capa{Neuronas: n_out, Activacion: Sigmoid})
+}
 
-	// incializamos los pesos
-	for i := 0; i < numero_dimensiones-1; i++ {
-		filas := dimensiones[i]
-		cols := dimensiones[i+1] // recordar que es para la matriz siguiente, las operaciones entre matirces
+// NuevaRed incializa los pesos con valores chicos random a un inicio
+func NuevaRed(entradas int, capas []Capa) *Red {
+	red := &Red{}
+	anterior := entradas
+	for _, capa := range capas {
+		filas := anterior
+		cols := capa.Neuronas // recordar que es para la matriz siguiente, las operaciones entre matirces
 
 		// [GO vs PY]: np.random.randn(f, c) * 0.1
 		// En Gonum creamos la matriz y llenamos los datos manualmente con rand.NormFloat64
-		// se incializa los pesos con valores chicos random a un inicio
 		wData := make([]float64, filas*cols)
-		for k := range wData { // aqui tenemos que hacer fila por fila
+		for k := range wData {
 			wData[k] = rand.NormFloat64() * 0.1
 		}
-		W = append(W, mat.NewDense(filas, cols, wData))
+		red.W = append(red.W, mat.NewDense(filas, cols, wData))
 
 		// El bias el valor que nos ayuda incializar los valores de la capa destino
-		// [GO vs PY]: np.random.randn(c) * 0.1
 		// NOTA: Para facilitar operaciones matriciales en Go, tratamos B como una matriz fila (1, cols)
 		bData := make([]float64, cols)
 		for k := range bData {
 			bData[k] = rand.NormFloat64() * 0.1
 		}
-		B = append(B, mat.NewDense(1, cols, bData))
+		red.B = append(red.B, mat.NewDense(1, cols, bData))
+		red.Activaciones = append(red.Activaciones, capa.Activacion)
+
+		anterior = capa.Neuronas
+	}
+	return red
+}
+
+// forward propaga las filas de x, regresa las activaciones (la primera es x) y los valores antes de activar
+func (red *Red) forward(x *mat.Dense) ([]*mat.Dense, []*mat.Dense) {
+	A := []*mat.Dense{x} // primer entrada como valores de activacion
+	Z := []*mat.Dense{}  // guardamos los valores antes de activar
+
+	for i := range red.W {
+		// [GO vs PY]: z_actual = np.dot(A[i], W[i]) + B[i]
+		filas, _ := A[i].Dims()
+		_, cW := red.W[i].Dims()
+		zActual := mat.NewDense(filas, cW, nil)
+		zActual.Mul(A[i], red.W[i])  // producto punto
+		sumarBias(zActual, red.B[i]) // es deplazamiento de la funcion
+
+		Z = append(Z, zActual)
+		A = append(A, red.Activaciones[i].Forward(zActual))
+	}
+	return A, Z
+}
+
+// Predecir propaga las entradas por la red, cada fila es una muestra,
+// aplica los escaladores asi que recibe y regresa los datos en su escala original
+func (red *Red) Predecir(x *mat.Dense) *mat.Dense {
+	if red.Entrada != nil {
+		x = red.Entrada.Normalizar(x)
+	}
+	A, _ := red.forward(x)
+	y := A[len(A)-1]
+	if red.Salida != nil {
+		y = red.Salida.Desnormalizar(y)
 	}
+	return y
+}
+
+// bufferLote matrices de un lote que se reusan en todas las epocas,
+// asi no se reserva memoria por cada muestra y capa
+type bufferLote struct {
+	X, Y *mat.Dense   // filas del lote
+	A    []*mat.Dense // activaciones, A[0] es X
+	Z    []*mat.Dense // valores antes de activar
+	D    []*mat.Dense // gradientes respecto a z
+	P    []*mat.Dense // gradiente que llega de la capa siguiente
+	G    *mat.Dense   // gradiente de la perdida respecto a la salida
+	unos *mat.Dense   // fila de unos para sumar los deltas de todas las muestras
+}
+
+func nuevoBufferLote(red *Red, filas, entradas, salidas int) *bufferLote {
+	b := &bufferLote{
+		X: mat.NewDense(filas, entradas, nil),
+		Y: mat.NewDense(filas, salidas, nil),
+		G: mat.NewDense(filas, salidas, nil),
+	}
+	b.A = append(b.A, b.X)
+	for _, w := range red.W {
+		_, c := w.Dims()
+		b.Z = append(b.Z, mat.NewDense(filas, c, nil))
+		b.A = append(b.A, mat.NewDense(filas, c, nil))
+		b.D = append(b.D, mat.NewDense(filas, c, nil))
+		b.P = append(b.P, mat.NewDense(filas, c, nil))
+	}
+	unos := make([]float64, filas)
+	for k := range unos {
+		unos[k] = 1
+	}
+	b.unos = mat.NewDense(1, filas, unos)
+	return b
+}
+
+// forward propaga las filas de b.X guardando cada paso en el buffer
+func (red *Red) forwardLote(b *bufferLote) {
+	for i := range red.W {
+		b.Z[i].Mul(b.A[i], red.W[i])
+		sumarBias(b.Z[i], red.B[i])
+		red.Activaciones[i].ForwardInto(b.A[i+1], b.Z[i])
+	}
+}
+
+// retropropagar calcula el gradiente promedio del lote para cada capa en gradW y gradB,
+// regresa la perdida sumada de las filas del lote
+func (red *Red) retropropagar(b *bufferLote, perdida Loss, gradW, gradB []*mat.Dense) float64 {
+	W := red.W
+	filas, _ := b.X.Dims()
+
+	// ===== Propagacion hacia adelante
+	red.forwardLote(b)
+
+	// la ultima activacion es la salida de esperada de Y obtenida
+	Y_obt := b.A[len(b.A)-1]
+	suma := perdida.Loss(b.Y, Y_obt)
+
+	// el gradiente de la perdida pasa por la derivada de la activacion de salida
+	ultima := len(W) - 1
+	perdida.GradientInto(b.G, b.Y, Y_obt)
+	red.Activaciones[ultima].BackwardInto(b.D[ultima], b.Z[ultima], b.G)
+
+	// propagamos el error
+	// [GO vs PY]: reversed(range(len(deltas) - 1))
+	for i := ultima - 1; i >= 0; i-- {
+		// [GO vs PY]: np.dot(deltas[i+1], W[i+1].T)
+		b.P[i].Mul(b.D[i+1], W[i+1].T())
+		red.Activaciones[i].BackwardInto(b.D[i], b.Z[i], b.P[i])
+	}
+
+	promedio := 1 / float64(filas)
+	for i := range W {
+		// [GO vs PY]: np.dot(A[i].T, deltas[i]) / len(lote)
+		gradW[i].Mul(b.A[i].T(), b.D[i])
+		gradW[i].Scale(promedio, gradW[i])
+
+		// [GO vs PY]: deltas[i].sum(axis=0) / len(lote)
+		gradB[i].Mul(b.unos, b.D[i])
+		gradB[i].Scale(promedio, gradB[i])
+	}
+	return suma
+}
+
+/*
+EntrenarRed entrena el modelo segun los patrones dados con la arquitectura y perdida de cfg,
+cada epoca revuelve las muestras y actualiza los pesos con el gradiente promedio de cada lote
+:param X: matriz de caractericas de forma (muestras,caracteristicas)
+:param Yd: matriz de etiquetas
+:return: red entrenada, perdida promedio de cada epoca en entrenamiento y validacion
+*/
+func EntrenarRed(X, Yd *mat.Dense, cfg ConfigRed) (*Red, Historial) {
+	red := NuevaRed(cfg.Entradas, cfg.Capas)
+	W, B := red.W, red.B
+	n_out := cfg.Capas[len(cfg.Capas)-1].Neuronas
 
 	ECM := 10.0 // nuestro error
 	ECM_historico := []float64{}
@@ -113,111 +289,144 @@ func Entrenar(X, Yd *mat.Dense, n_in, n_out int, n_layers []int, lr float64, epo
 
 	// Obtener número de muestras (filas de X)
 	nMuestras, _ := X.Dims()
+	tamLote := min(max(cfg.TamLote, 1), nMuestras)
 
-	for ECM > 0.0 && epoch <= epoch_max {
-		suma_ecm := 0.0
+	// el ultimo lote puede quedar mas chico, tiene su propio buffer
+	lote := nuevoBufferLote(red, tamLote, cfg.Entradas, n_out)
+	var resto *bufferLote
+	if r := nMuestras % tamLote; r != 0 {
+		resto = nuevoBufferLote(red, r, cfg.Entradas, n_out)
+	}
 
-		for p := 0; p < nMuestras; p++ { // recorremos los patrones
+	// los gradientes de los pesos no dependen del tamaño del lote
+	gradW := make([]*mat.Dense, len(W))
+	gradB := make([]*mat.Dense, len(B))
+	for i := range W {
+		r, c := W[i].Dims()
+		gradW[i] = mat.NewDense(r, c, nil)
+		gradB[i] = mat.NewDense(1, c, nil)
+	}
 
-			// ===== Propagacion hacia adelante
+	orden := make([]int, nMuestras)
+	for i := range orden {
+		orden[i] = i
+	}
 
-			// [GO vs PY]: A = [X[p]]. En Gonum extraemos la fila 'p' como una nueva matriz (View/Slice).
-			// Slice(i, k, j, l) toma filas [i,k) y columnas [j,l).
-			rowX := X.Slice(p, p+1, 0, n_in).(*mat.Dense)
+	optimizador := cfg.Optimizador
+	if optimizador == nil {
+		optimizador = SGD{}
+	}
+	ajuste := cfg.AjusteLR
+	if ajuste == nil {
+		ajuste = ConstantLR{}
+	}
 
-			A := []*mat.Dense{rowX} // primer entrada como valores de activacion
-			Z := []*mat.Dense{}     // guardamos los valores antes de activar
+	// copia de los mejores pesos segun la validacion
+	validar := cfg.Validacion != nil && cfg.Validacion.Filas() > 0
+	var mejorW, mejorB []*mat.Dense
+	mejorPerdida := math.Inf(1)
+	historial := Historial{}
+	if validar {
+		for i := range W {
+			mejorW = append(mejorW, mat.DenseCopyOf(W[i]))
+			mejorB = append(mejorB, mat.DenseCopyOf(B[i]))
+		}
+	}
 
-			for i := 0; i < len(W); i++ {
-				// recordar que es cada valor de activacion por cada peso
-				// [GO vs PY]: z_actual = np.dot(A[i], W[i]) + B[i]
-				// En Gonum: Mul realiza la multiplicación matricial.
-				zActual := mat.NewDense(1, B[i].RawMatrix().Cols, nil)
-				zActual.Mul(A[i], W[i]) // producto punto
-
-				// [GO vs PY]: Suma de Bias. A diferencia de numpy que hace broadcasting automático,
-				// aquí usamos Add. Como definimos B como (1, cols), las dimensiones coinciden.
-				zActual.Add(zActual, B[i]) // es deplazamiento de la funcion
-
-				Z = append(Z, zActual)
-				A = append(A, applySigmoide(zActual))
+	for ECM > 0.0 && epoch <= cfg.EpocasMax {
+		suma_ecm := 0.0
+		lr := ajuste.Rate(cfg.LR, epoch)
+		rand.Shuffle(len(orden), func(a, b int) {
+			orden[a], orden[b] = orden[b], orden[a]
+		})
+
+		for inicio := 0; inicio < nMuestras; inicio += tamLote {
+			b := lote
+			if inicio+tamLote > nMuestras {
+				b = resto
 			}
+			filas, _ := b.X.Dims()
 
-			// la ultima activacion es la salida de esperada de Y obtenida
-			Y_obt := A[len(A)-1]
-
-			// guardamos los errores para poder propagar hacia atras
-			deltas := make([]*mat.Dense, len(W))
-
-			// recordar que devuelve un arreglo de los errores (distancias)
-			// [GO vs PY]: error = Yd[p] - Y_obt
-			yDeseada := Yd.Slice(p, p+1, 0, n_out).(*mat.Dense)
-			errorMat := mat.NewDense(1, n_out, nil)
-			errorMat.Sub(yDeseada, Y_obt)
-
-			// [GO vs PY]: suma_ecm += np.sum(error**2)
-			// Iteramos manualmente para sumar los cuadrados
-			er, ec := errorMat.Dims()
-			for r := 0; r < er; r++ {
-				for c := 0; c < ec; c++ {
-					val := errorMat.At(r, c)
-					suma_ecm += val * val
-				}
+			// copiamos las filas que tocan a este lote
+			for k := 0; k < filas; k++ {
+				p := orden[inicio+k]
+				b.X.SetRow(k, X.RawRowView(p))
+				b.Y.SetRow(k, Yd.RawRowView(p))
 			}
 
-			// la (Deseado - Obtenido) * derivada que conecta los errores entre capas
-			// [GO vs PY]: deltas[-1] = error * d_sigmoide_z(Z[-1])
-			// MulElem es multiplicación elemento a elemento (Hadamard product), no matricial.
-			dSigZ := applyDSigmoide(Z[len(Z)-1])
-			deltaLast := mat.NewDense(1, n_out, nil)
-			deltaLast.MulElem(errorMat, dSigZ)
-			deltas[len(deltas)-1] = deltaLast
-
-			// propagamos el error
-			// [GO vs PY]: reversed(range(len(deltas) - 1))
-			for i := len(deltas) - 2; i >= 0; i-- {
-				// [GO vs PY]: np.dot(deltas[i+1], W[i+1].T)
-				// Necesitamos la transpuesta de W[i+1].
-				wNextT := W[i+1].T()
-				deltaProp := mat.NewDense(1, dimensiones[i+1], nil)
-				deltaProp.Mul(deltas[i+1], wNextT)
-
-				// [GO vs PY]: ... * d_sigmoide_z(Z[i])
-				// Multiplicación elemento a elemento
-				dSigZi := applyDSigmoide(Z[i])
-				deltaCurrent := mat.NewDense(1, dimensiones[i+1], nil)
-				deltaCurrent.MulElem(deltaProp, dSigZi)
-
-				deltas[i] = deltaCurrent
-			}
+			suma_ecm += red.retropropagar(b, cfg.Perdida, gradW, gradB)
 
-			// actualizamos pesos
+			// actualizamos pesos con el promedio del lote, el optimizador decide cuanto moverlos
 			for i := 0; i < len(W); i++ {
-				// [GO vs PY]: W[i] += lr * np.outer(A[i], deltas[i])
-				// np.outer(A[i], deltas[i]) donde A[i] es (1, N) y deltas[i] es (1, M) en Numpy
-				// produce una matriz (N, M).
-				// En Gonum: A[i] es (1, N). Transponemos A[i] a (N, 1).
-				// (N, 1) x (1, M) = (N, M).
-				changeW := mat.NewDense(dimensiones[i], dimensiones[i+1], nil)
-				changeW.Mul(A[i].T(), deltas[i])
-				changeW.Scale(lr, changeW) // Multiplicar por learning rate
-
-				W[i].Add(W[i], changeW)
-
-				// [GO vs PY]: B[i] += lr * deltas[i]
-				changeB := mat.NewDense(1, dimensiones[i+1], nil)
-				changeB.Scale(lr, deltas[i])
-				B[i].Add(B[i], changeB)
+				// [GO vs PY]: l2 * W[i]
+				if cfg.L2 > 0 {
+					gradW[i].Apply(func(r, c int, v float64) float64 {
+						return v + cfg.L2*W[i].At(r, c)
+					}, gradW[i])
+				}
+				optimizador.Step(W[i], gradW[i], lr)
+				optimizador.Step(B[i], gradB[i], lr)
 			}
 		}
 
-		// [GO vs PY]: ECM = 0.5 * (suma_ecm / len(X))
-		ECM = 0.5 * (suma_ecm / float64(nMuestras))
+		// [GO vs PY]: ECM = suma_perdida / len(X), con mse es el 0.5 * (suma_ecm / len(X)) original
+		ECM = suma_ecm / float64(nMuestras)
 		ECM_historico = append(ECM_historico, ECM)
 		epoch++
+
+		if !validar {
+			historial.MejorEpoca = epoch - 1
+			continue
+		}
+
+		perdida := cfg.Perdida.Loss(cfg.Validacion.Y, red.Predecir(cfg.Validacion.X)) / float64(cfg.Validacion.Filas())
+		historial.Validacion = append(historial.Validacion, perdida)
+		if perdida < mejorPerdida {
+			mejorPerdida = perdida
+			historial.MejorEpoca = epoch - 1
+			for i := range W {
+				mejorW[i].Copy(W[i])
+				mejorB[i].Copy(B[i])
+			}
+		}
+		// early stopping: la validacion lleva Paciencia epocas sin mejorar
+		if cfg.Paciencia > 0 && epoch-1-historial.MejorEpoca >= cfg.Paciencia {
+			break
+		}
 	}
 
-	return W, B, ECM_historico
+	// restauramos los pesos de la epoca que mejor generalizo
+	if validar {
+		for i := range W {
+			W[i].Copy(mejorW[i])
+			B[i].Copy(mejorB[i])
+		}
+	}
+
+	historial.Entrenamiento = ECM_historico
+	return red, historial
+}
+
+/*
+Entrena el modelo segun los patrones dados, sigmoide en todas las capas y error cuadratico
+:param X: matriz de caractericas de forma (muestras,caracteristicas)
+:param Yd: matriz de etiquetas
+:param n_in: numero de neuronas entradas
+:param n_out: numero de neuronas de salida
+:param n_layers: lista de numero de neuronas ocultas por capa
+:param lr: learning rate
+:param epoch_max: numero de epocas maximas
+:return: W (pesos), B (bias), ECM_historico
+*/
+func Entrenar(X, Yd *mat.Dense, n_in, n_out int, n_layers []int, lr float64, epoch_max int) ([]*mat.Dense, []*mat.Dense, []float64) {
+	red, historial := EntrenarRed(X, Yd, ConfigRed{
+		Entradas:  n_in,
+		Capas:     sigmoides(n_layers, n_out),
+		Perdida:   MSELoss{},
+		LR:        lr,
+		EpocasMax: epoch_max,
+	})
+	return red.W, red.B, historial.Entrenamiento
 }
 
 /*
@@ -267,71 +476,6 @@ func NormalizarZScore(X *mat.Dense) (*mat.Dense, []float64, []float64) {
 	return X_norm, media, desviacion
 }
 
-/*
-Ajusta los pesos y bias de la primera capa para aceptar datos sin normalizar.
-*/
-func Desnormalizar(W []*mat.Dense, B []*mat.Dense, media, desviacion []float64) ([]*mat.Dense, []*mat.Dense) {
-	// [GO vs PY]: w.copy().
-	// En Gonum usamos CloneFrom para hacer una copia profunda (deep copy)
-	W_real := make([]*mat.Dense, len(W))
-	B_real := make([]*mat.Dense, len(B))
-	for i := range W {
-		// Usamos Copy con dimensiones explicitas para evitar pánico con dimensiones 0
-		r, c := W[i].Dims()
-		wr := mat.NewDense(r, c, nil)
-		wr.Copy(W[i])
-		W_real[i] = wr
-
-		rB, cB := B[i].Dims()
-		br := mat.NewDense(rB, cB, nil)
-		br.Copy(B[i])
-		B_real[i] = br
-	}
-
-	// Ajustamos solo la PRIMERA capa (índice 0)
-	// W_real[0] tiene forma (caracteristicas, neuronas_ocultas)
-	rows, cols := W_real[0].Dims()
-
-	// 1. Ajustar Pesos: W / sigma (columna por columna)
-	// [GO vs PY]: W_real[0][:, i] = W_real[0][:, i] / desviacion
-	// Nota: En la versión python divide la columna i de W por el vector desviacion (broadcasting sobre filas).
-	// Matemáticamente: W_new[j, i] = W_old[j, i] / sigma[j] (donde j es la característica de entrada)
-	for j := 0; j < rows; j++ { // j itera sobre características (filas de W)
-		for i := 0; i < cols; i++ { // i itera sobre neuronas ocultas (columnas de W)
-			// Dividimos el peso que conecta la entrada j con la neurona i por la std de la entrada j
-			val := W_real[0].At(j, i)
-			W_real[0].Set(j, i, val/desviacion[j])
-		}
-	}
-
-	// 2. Ajustar Bias: B - sum(mu * W / sigma)
-	// [GO vs PY]: ajuste_bias = np.dot(media, W_real[0])
-	// media es (1, n_features), W_real[0] es (n_features, n_hidden).
-	mediaVec := mat.NewDense(1, len(media), media)
-	ajusteBias := mat.NewDense(1, cols, nil)
-	ajusteBias.Mul(mediaVec, W_real[0])
-
-	// [GO vs PY]: B_real[0] = B_real[0] - ajuste_bias
-	B_real[0].Sub(B_real[0], ajusteBias)
-
-	return W_real, B_real
-}
-
-func Predecir(x *mat.Dense, W []*mat.Dense, B []*mat.Dense) *mat.Dense {
-	activacion := x
-	for i := 0; i < len(W); i++ {
-		// [GO vs PY]: z = np.dot(activacion, W[i]) + B[i]
-		_, cW := W[i].Dims()
-		z := mat.NewDense(1, cW, nil)
-		z.Mul(activacion, W[i])
-		z.Add(z, B[i])
-
-		// La nueva activación es el resultado de la sigmoide
-		activacion = applySigmoide(z)
-	}
-	return activacion
-}
-
 // Escalonar convierte probabilidades en 0 o 1
 func Escalonar(Z *mat.Dense) []float64 {
 	_, cols := Z.Dims()
@@ -346,82 +490,3 @@ func Escalonar(Z *mat.Dense) []float64 {
 	}
 	return res
 }
-
-// Estructura auxiliar para guardar en archivo (Gob)
-type ModelDump struct {
-	WData [][]float64
-	WRows []int
-	WCols []int
-	BData [][]float64
-	BRows []int
-	BCols []int
-}
-
-func GuardarModelo(nombreModelo string, W []*mat.Dense, B []*mat.Dense) {
-	// En Go no existe np.savez directo, usamos encoding/gob para serializar
-	dump := ModelDump{}
-
-	for _, w := range W {
-		r, c := w.Dims()
-		dump.WRows = append(dump.WRows, r)
-		dump.WCols = append(dump.WCols, c)
-		// Guardamos los datos crudos directos del Dense (row-major), que es lo que espera NewDense al cargar
-		dump.WData = append(dump.WData, w.RawMatrix().Data)
-	}
-	for _, b := range B {
-		r, c := b.Dims()
-		dump.BRows = append(dump.BRows, r)
-		dump.BCols = append(dump.BCols, c)
-		dump.BData = append(dump.BData, b.RawMatrix().Data)
-	}
-
-	file, err := os.Create(nombreModelo + ".gob")
-	if err != nil {
-		fmt.Println("Error creando archivo:", err)
-		return
-	}
-	defer file.Close()
-
-	encoder := gob.NewEncoder(file)
-	encoder.Encode(dump)
-	fmt.Println("El calculo de W y B se guardo en disco: " + nombreModelo + ".gob !!!")
-}
-
-/*
-CargarModelo lee un archivo .gob y reconstruye las matrices de pesos y bias.
-:param nombreModelo: nombre del archivo (sin extensión .gob)
-:return: W ([]*mat.Dense), B ([]*mat.Dense), error
-*/
-func CargarModelo(nombreModelo string) ([]*mat.Dense, []*mat.Dense, error) {
-	// 1. Abrir el archivo
-	file, err := os.Open(nombreModelo + ".gob")
-	if err != nil {
-		return nil, nil, fmt.Errorf("no se pudo abrir el archivo: %v", err)
-	}
-	defer file.Close()
-
-	// 2. Decodificar la estructura ModelDump
-	var dump ModelDump
-	decoder := gob.NewDecoder(file)
-	err = decoder.Decode(&dump)
-	if err != nil {
-		return nil, nil, fmt.Errorf("error al decodificar el modelo: %v", err)
-	}
-
-	// 3. Reconstruir los Pesos (W)
-	W := make([]*mat.Dense, len(dump.WRows))
-	for i := 0; i < len(dump.WRows); i++ {
-		// NewDense toma (filas, columnas, data)
-		// Gonum copiará el slice dump.WData[i] para crear la matriz
-		W[i] = mat.NewDense(dump.WRows[i], dump.WCols[i], dump.WData[i])
-	}
-
-	// 4. Reconstruir los Bias (B)
-	B := make([]*mat.Dense, len(dump.BRows))
-	for i := 0; i < len(dump.BRows); i++ {
-		B[i] = mat.NewDense(dump.BRows[i], dump.BCols[i], dump.BData[i])
-	}
-
-	fmt.Printf("Modelo '%s.gob' cargado correctamente.\n", nombreModelo)
-	return W, B, nil
-}
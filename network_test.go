@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// datosSinteticos filas con la forma de la tabla de puntajes (puntaje, tiempo) -> lapso,
+// asi se puede medir sin depender de cuantas partidas haya en la base de datos
+func datosSinteticos(n int) (*mat.Dense, *mat.Dense) {
+	X := mat.NewDense(n, 2, nil)
+	Yd := mat.NewDense(n, 1, nil)
+	for i := 0; i < n; i++ {
+		puntaje := rand.Float64() * MaxAjolotePoints
+		tiempo := 30 + rand.Float64()*270
+		X.Set(i, 0, puntaje)
+		X.Set(i, 1, tiempo)
+		Yd.Set(i, 0, EnemyElapseMin+(EnemyElapseMax-EnemyElapseMin)*puntaje/MaxAjolotePoints)
+	}
+	X, _, _ = NormalizarZScore(X)
+	Yd, _, _ = NormalizarZScore(Yd)
+	return X, Yd
+}
+
+// el gradiente promedio del lote debe ser la derivada de la perdida promedio respecto a cada peso
+func TestRetropropagarGradiente(t *testing.T) {
+	casos := []struct {
+		nombre  string
+		capas   []Capa
+		perdida Loss
+		salida  func(r, c int) *mat.Dense
+	}{
+		{"regresion", []Capa{{Neuronas: 4, Activacion: Tanh}, {Neuronas: 1, Activacion: Linear}}, MSELoss{}, matrizAleatoria},
+		{"huber", []Capa{{Neuronas: 3, Activacion: Sigmoid}, {Neuronas: 2, Activacion: Tanh}, {Neuronas: 2, Activacion: Linear}}, HuberLoss{Delta: HuberDelta}, matrizAleatoria},
+		{"clasificacion", []Capa{{Neuronas: 5, Activacion: Tanh}, {Neuronas: 3, Activacion: Softmax{}}}, CrossEntropyLoss{}, unoCaliente},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nombre, func(t *testing.T) {
+			const filas, entradas = 6, 3
+			red := NuevaRed(entradas, caso.capas)
+			salidas := caso.capas[len(caso.capas)-1].Neuronas
+			b := nuevoBufferLote(red, filas, entradas, salidas)
+			b.X.Copy(matrizAleatoria(filas, entradas))
+			b.Y.Copy(caso.salida(filas, salidas))
+
+			gradW := make([]*mat.Dense, len(red.W))
+			gradB := make([]*mat.Dense, len(red.B))
+			for i := range red.W {
+				r, c := red.W[i].Dims()
+				gradW[i] = mat.NewDense(r, c, nil)
+				gradB[i] = mat.NewDense(1, c, nil)
+			}
+			red.retropropagar(b, caso.perdida, gradW, gradB)
+
+			// perdida promedio del lote, usa otros gradientes para no pisar los analiticos
+			auxW := make([]*mat.Dense, len(gradW))
+			auxB := make([]*mat.Dense, len(gradB))
+			for i := range gradW {
+				auxW[i] = mat.DenseCopyOf(gradW[i])
+				auxB[i] = mat.DenseCopyOf(gradB[i])
+			}
+			perdida := func() float64 {
+				return red.retropropagar(b, caso.perdida, auxW, auxB) / filas
+			}
+
+			for i := range red.W {
+				compararMatrices(t, fmt.Sprintf("W%d", i), gradW[i], gradienteNumerico(red.W[i], perdida), toleranciaGradient)
+				compararMatrices(t, fmt.Sprintf("B%d", i), gradB[i], gradienteNumerico(red.B[i], perdida), toleranciaGradient)
+			}
+		})
+	}
+}
+
+func TestEntrenarRedAprende(t *testing.T) {
+	// 50 muestras con lotes de 8, el ultimo lote queda de 2
+	X, Yd := datosSinteticos(50)
+	for _, tam := range []int{1, 8, 50} {
+		t.Run(fmt.Sprintf("lote_%d", tam), func(t *testing.T) {
+			_, historial := EntrenarRed(X, Yd, ConfigRed{
+				Entradas:  2,
+				Capas:     []Capa{{Neuronas: 4, Activacion: Tanh}, {Neuronas: 1, Activacion: Linear}},
+				Perdida:   MSELoss{},
+				LR:        0.05,
+				EpocasMax: 300,
+				TamLote:   tam,
+			})
+			inicial := historial.Entrenamiento[0]
+			final := historial.Entrenamiento[len(historial.Entrenamiento)-1]
+			if final > inicial/10 {
+				t.Errorf("la perdida solo bajo de %g a %g", inicial, final)
+			}
+		})
+	}
+}
+
+// BenchmarkEntrenarRed cuanto tarda EntrenarRed con distintos tamaños de lote
+func BenchmarkEntrenarRed(b *testing.B) {
+	const muestras, epocas = 2000, 20
+	X, Yd := datosSinteticos(muestras)
+
+	for _, tam := range []int{1, 8, MLPBatchSize, 128, muestras} {
+		cfg := ConfigRed{
+			Entradas: 2,
+			Capas: []Capa{
+				{Neuronas: 4, Activacion: Tanh},
+				{Neuronas: 1, Activacion: Linear},
+			},
+			Perdida:   MSELoss{},
+			LR:        0.01,
+			EpocasMax: epocas - 1, // EntrenarRed incluye la epoca EpocasMax
+			TamLote:   tam,
+		}
+		b.Run(fmt.Sprintf("lote_%d", tam), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				EntrenarRed(X, Yd, cfg)
+			}
+		})
+	}
+}
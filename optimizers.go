@@ -0,0 +1,242 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Optimizer decide como se mueven los pesos a partir de su gradiente,
+// guarda su propio estado por cada matriz de parametros
+type Optimizer interface {
+	Name() string
+	// Step actualiza p con el gradiente g (promedio del lote), lr es la tasa de la epoca actual
+	Step(p, g *mat.Dense, lr float64)
+}
+
+// las matrices de la red se crean con NewDense, asi que sus datos son contiguos
+// y se pueden recorrer directo sin pasar por At y Set
+func datos(m *mat.Dense) []float64 {
+	return m.RawMatrix().Data
+}
+
+// estadoDe regresa la matriz de estado de p, la crea en ceros la primera vez
+func estadoDe(estados map[*mat.Dense]*mat.Dense, p *mat.Dense) []float64 {
+	e, ok := estados[p]
+	if !ok {
+		r, c := p.Dims()
+		e = mat.NewDense(r, c, nil)
+		estados[p] = e
+	}
+	return datos(e)
+}
+
+// SGD descenso de gradiente sin estado, lo que hacia Entrenar originalmente
+type SGD struct{}
+
+func (SGD) Name() string {
+	return "sgd"
+}
+
+func (SGD) Step(p, g *mat.Dense, lr float64) {
+	pd, gd := datos(p), datos(g)
+	for k := range pd {
+		pd[k] -= lr * gd[k]
+	}
+}
+
+// Momentum acumula una velocidad para no frenarse en los valles planos
+type Momentum struct {
+	Beta        float64 // fraccion de la velocidad que se conserva
+	Nesterov    bool    // mira hacia donde lo lleva la velocidad antes de corregir
+	velocidades map[*mat.Dense]*mat.Dense
+}
+
+func NewMomentum(beta float64) *Momentum {
+	return &Momentum{Beta: beta, velocidades: map[*mat.Dense]*mat.Dense{}}
+}
+
+func NewNesterov(beta float64) *Momentum {
+	m := NewMomentum(beta)
+	m.Nesterov = true
+	return m
+}
+
+func (m *Momentum) Name() string {
+	if m.Nesterov {
+		return "nesterov"
+	}
+	return "momentum"
+}
+
+func (m *Momentum) Step(p, g *mat.Dense, lr float64) {
+	pd, gd := datos(p), datos(g)
+	v := estadoDe(m.velocidades, p)
+	for k := range pd {
+		anterior := v[k]
+		v[k] = m.Beta*v[k] - lr*gd[k]
+		if m.Nesterov {
+			// forma de Sutskever: p += -beta * v_anterior + (1 + beta) * v
+			pd[k] += -m.Beta*anterior + (1+m.Beta)*v[k]
+		} else {
+			pd[k] += v[k]
+		}
+	}
+}
+
+// RMSProp divide el paso entre la magnitud reciente del gradiente de cada peso
+type RMSProp struct {
+	Rho       float64 // que tanto pesa el historial del gradiente al cuadrado
+	Epsilon   float64
+	cuadrados map[*mat.Dense]*mat.Dense
+}
+
+func NewRMSProp(rho float64) *RMSProp {
+	return &RMSProp{Rho: rho, Epsilon: OptimizerEpsilon, cuadrados: map[*mat.Dense]*mat.Dense{}}
+}
+
+func (r *RMSProp) Name() string {
+	return "rmsprop"
+}
+
+func (r *RMSProp) Step(p, g *mat.Dense, lr float64) {
+	pd, gd := datos(p), datos(g)
+	s := estadoDe(r.cuadrados, p)
+	for k := range pd {
+		s[k] = r.Rho*s[k] + (1-r.Rho)*gd[k]*gd[k]
+		pd[k] -= lr * gd[k] / (math.Sqrt(s[k]) + r.Epsilon)
+	}
+}
+
+// Adam momentum y RMSProp juntos, con correccion del sesgo de los primeros pasos
+type Adam struct {
+	Beta1, Beta2 float64
+	Epsilon      float64
+	momentos     map[*mat.Dense]*mat.Dense
+	cuadrados    map[*mat.Dense]*mat.Dense
+	pasos        map[*mat.Dense]int
+}
+
+func NewAdam(beta1, beta2 float64) *Adam {
+	return &Adam{
+		Beta1:     beta1,
+		Beta2:     beta2,
+		Epsilon:   OptimizerEpsilon,
+		momentos:  map[*mat.Dense]*mat.Dense{},
+		cuadrados: map[*mat.Dense]*mat.Dense{},
+		pasos:     map[*mat.Dense]int{},
+	}
+}
+
+func (a *Adam) Name() string {
+	return "adam"
+}
+
+func (a *Adam) Step(p, g *mat.Dense, lr float64) {
+	pd, gd := datos(p), datos(g)
+	m := estadoDe(a.momentos, p)
+	v := estadoDe(a.cuadrados, p)
+	a.pasos[p]++
+	t := float64(a.pasos[p])
+	correccion1 := 1 - math.Pow(a.Beta1, t)
+	correccion2 := 1 - math.Pow(a.Beta2, t)
+	for k := range pd {
+		m[k] = a.Beta1*m[k] + (1-a.Beta1)*gd[k]
+		v[k] = a.Beta2*v[k] + (1-a.Beta2)*gd[k]*gd[k]
+		pd[k] -= lr * (m[k] / correccion1) / (math.Sqrt(v[k]/correccion2) + a.Epsilon)
+	}
+}
+
+// OptimizerByName crea un optimizador nuevo con sus parametros por defecto
+func OptimizerByName(nombre string) (Optimizer, error) {
+	switch nombre {
+	case "sgd":
+		return SGD{}, nil
+	case "momentum":
+		return NewMomentum(MomentumBeta), nil
+	case "nesterov":
+		return NewNesterov(MomentumBeta), nil
+	case "rmsprop":
+		return NewRMSProp(RMSPropRho), nil
+	case "adam":
+		return NewAdam(AdamBeta1, AdamBeta2), nil
+	}
+	return nil, fmt.Errorf("optimizador desconocido: %s", nombre)
+}
+
+// LRSchedule cambia la tasa de aprendizaje conforme avanzan las epocas
+type LRSchedule interface {
+	Name() string
+	Rate(base float64, epoca int) float64
+}
+
+// ConstantLR la tasa no cambia
+type ConstantLR struct{}
+
+func (ConstantLR) Name() string {
+	return "fija"
+}
+
+func (ConstantLR) Rate(base float64, epoca int) float64 {
+	return base
+}
+
+// StepLR multiplica la tasa por Factor cada Cada epocas
+type StepLR struct {
+	Cada   int
+	Factor float64
+}
+
+func (StepLR) Name() string {
+	return "escalon"
+}
+
+func (s StepLR) Rate(base float64, epoca int) float64 {
+	return base * math.Pow(s.Factor, float64(epoca/s.Cada))
+}
+
+// ExponentialLR multiplica la tasa por Gamma en cada epoca
+type ExponentialLR struct {
+	Gamma float64
+}
+
+func (ExponentialLR) Name() string {
+	return "exponencial"
+}
+
+func (e ExponentialLR) Rate(base float64, epoca int) float64 {
+	return base * math.Pow(e.Gamma, float64(epoca))
+}
+
+// CosineLR baja la tasa siguiendo medio coseno desde base hasta base*Min en Epocas
+type CosineLR struct {
+	Epocas int
+	Min    float64 // fraccion de la tasa base a la que se llega al final
+}
+
+func (CosineLR) Name() string {
+	return "coseno"
+}
+
+func (c CosineLR) Rate(base float64, epoca int) float64 {
+	avance := math.Min(float64(epoca)/float64(max(c.Epocas, 1)), 1)
+	minimo := base * c.Min
+	return minimo + (base-minimo)*0.5*(1+math.Cos(math.Pi*avance))
+}
+
+// ScheduleByName crea el ajuste de la tasa por su nombre, epocas es la duracion del entrenamiento
+func ScheduleByName(nombre string, epocas int) (LRSchedule, error) {
+	switch nombre {
+	case "fija":
+		return ConstantLR{}, nil
+	case "escalon":
+		return StepLR{Cada: max(epocas/LRStepCount, 1), Factor: LRStepFactor}, nil
+	case "exponencial":
+		// al final de las epocas la tasa queda en LRMinFactor de la original
+		return ExponentialLR{Gamma: math.Pow(LRMinFactor, 1/float64(max(epocas, 1)))}, nil
+	case "coseno":
+		return CosineLR{Epocas: epocas, Min: LRMinFactor}, nil
+	}
+	return nil, fmt.Errorf("ajuste de tasa desconocido: %s", nombre)
+}
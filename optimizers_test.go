@@ -0,0 +1,104 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+var nombresOptimizadores = []string{"sgd", "momentum", "nesterov", "rmsprop", "adam"}
+
+// todos deben llegar al minimo de 0.5 * |p - c|^2, cuyo gradiente es p - c
+func TestOptimizadoresConvergen(t *testing.T) {
+	centro := []float64{3, -2, 0.5, 1}
+	for _, nombre := range nombresOptimizadores {
+		t.Run(nombre, func(t *testing.T) {
+			o, err := OptimizerByName(nombre)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := mat.NewDense(2, 2, nil)
+			g := mat.NewDense(2, 2, nil)
+			for paso := 0; paso < 2000; paso++ {
+				g.Apply(func(i, j int, v float64) float64 {
+					return p.At(i, j) - centro[i*2+j]
+				}, g)
+				// las tasas adaptativas dan pasos de tamaño lr, hay que bajarla para que se asienten
+				o.Step(p, g, 0.05*CosineLR{Epocas: 2000, Min: 0.001}.Rate(1, paso))
+			}
+			compararMatrices(t, "p", p, mat.NewDense(2, 2, centro), 1e-3)
+		})
+	}
+}
+
+// el estado es por matriz: usar un optimizador con dos matrices es igual que usar uno para cada una
+func TestOptimizadoresEstadoPorMatriz(t *testing.T) {
+	for _, nombre := range nombresOptimizadores {
+		t.Run(nombre, func(t *testing.T) {
+			compartido, _ := OptimizerByName(nombre)
+			solo1, _ := OptimizerByName(nombre)
+			solo2, _ := OptimizerByName(nombre)
+
+			a, b := mat.NewDense(1, 3, nil), mat.NewDense(1, 3, nil)
+			a2, b2 := mat.NewDense(1, 3, nil), mat.NewDense(1, 3, nil)
+			ga := mat.NewDense(1, 3, []float64{1, -2, 0.5})
+			gb := mat.NewDense(1, 3, []float64{-4, 0.1, 3})
+			for paso := 0; paso < 5; paso++ {
+				compartido.Step(a, ga, 0.1)
+				compartido.Step(b, gb, 0.1)
+				solo1.Step(a2, ga, 0.1)
+				solo2.Step(b2, gb, 0.1)
+			}
+			compararMatrices(t, "a", a, a2, 0)
+			compararMatrices(t, "b", b, b2, 0)
+		})
+	}
+}
+
+// con la correccion del sesgo el primer paso de adam mide lr en cada peso sin importar el gradiente
+func TestAdamPrimerPaso(t *testing.T) {
+	p := mat.NewDense(1, 3, nil)
+	NewAdam(AdamBeta1, AdamBeta2).Step(p, mat.NewDense(1, 3, []float64{100, -0.01, 2}), 0.1)
+	compararMatrices(t, "p", p, mat.NewDense(1, 3, []float64{-0.1, 0.1, -0.1}), 1e-6)
+}
+
+// rmsprop en el primer paso divide entre sqrt(1 - rho) * |g|
+func TestRMSPropPrimerPaso(t *testing.T) {
+	p := mat.NewDense(1, 2, nil)
+	NewRMSProp(RMSPropRho).Step(p, mat.NewDense(1, 2, []float64{4, -0.5}), 0.1)
+	paso := 0.1 / math.Sqrt(1-RMSPropRho)
+	compararMatrices(t, "p", p, mat.NewDense(1, 2, []float64{-paso, paso}), 1e-6)
+}
+
+func TestAjustesLR(t *testing.T) {
+	const base, epocas = 0.1, 100
+	for _, nombre := range []string{"fija", "escalon", "exponencial", "coseno"} {
+		t.Run(nombre, func(t *testing.T) {
+			a, err := ScheduleByName(nombre, epocas)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r := a.Rate(base, 0); r != base {
+				t.Errorf("la primera epoca usa %g, se esperaba %g", r, base)
+			}
+			// nunca sube
+			anterior := base
+			for e := 1; e <= epocas; e++ {
+				r := a.Rate(base, e)
+				if r > anterior+1e-15 {
+					t.Fatalf("la tasa subio de %g a %g en la epoca %d", anterior, r, e)
+				}
+				anterior = r
+			}
+		})
+	}
+
+	// los ajustes que bajan hasta LRMinFactor terminan ahi
+	for _, nombre := range []string{"exponencial", "coseno"} {
+		a, _ := ScheduleByName(nombre, epocas)
+		if r := a.Rate(base, epocas); math.Abs(r-base*LRMinFactor) > 1e-12 {
+			t.Errorf("%s termina en %g, se esperaba %g", nombre, r, base*LRMinFactor)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import "math"
+
+// LostSightBehavior lo implementan los comportamientos que saben que hacer
+// cuando el enemigo no ve al jugador, el resto busca en su ultima posicion conocida
+type LostSightBehavior interface {
+	LostTarget(e *Enemy) *Node
+}
+
+// CanSeePlayer indica si el jugador esta dentro del alcance de vision y ningun muro lo tapa
+func (e *Enemy) CanSeePlayer() bool {
+	if e.Juego.Effects.Active(PowerUpInvisible) {
+		return false
+	}
+
+	if e.SightRange == 0 {
+		return true // sin alcance de vision, siempre sabe donde esta el jugador
+	}
+
+	jugador := e.TargetPlayer().NodePosition
+	dx := float64(jugador.X - e.NodePosition.X)
+	dy := float64(jugador.Y - e.NodePosition.Y)
+	if math.Sqrt(dx*dx+dy*dy) > float64(e.SightRange) {
+		return false
+	}
+
+	return e.Juego.Maze.LineOfSight(e.NodePosition, jugador)
+}
+
+// ChaseTarget decide el objetivo en modo persecucion, solo persigue mientras ve al jugador
+func (e *Enemy) ChaseTarget() *Node {
+	if e.CanSeePlayer() {
+		e.LastKnown = e.TargetPlayer().NodePosition.Clone()
+		e.SearchTargets = nil
+		e.Searching = false
+		// si el coordinador le asigno una salida del jugador, la cubre en lugar de perseguirlo
+		if salida, ok := e.Juego.Coordinator.Assignments[e]; ok {
+			return salida
+		}
+		return e.Behavior.Target(e)
+	}
+
+	// con el jugador invisible no tienen rastro que seguir, solo deambulan
+	if e.Juego.Effects.Active(PowerUpInvisible) {
+		return e.wander.Target(e)
+	}
+
+	if lb, ok := e.Behavior.(LostSightBehavior); ok {
+		return lb.LostTarget(e)
+	}
+
+	return e.SearchTarget()
+}
+
+// SearchTarget se dirige a la ultima posicion conocida del jugador,
+// al llegar recorre puntos que se alejan cada vez mas de ella
+func (e *Enemy) SearchTarget() *Node {
+	if e.LastKnown == nil {
+		return e.wander.Target(e) // ya no sabe nada del jugador, deambula
+	}
+
+	if !e.Searching {
+		if !e.NodePosition.Equal(e.LastKnown) {
+			return e.LastKnown
+		}
+		// llegamos y no esta, empezamos a buscar alrededor
+		e.Searching = true
+		e.SearchTargets = e.Maze().SearchPattern(e.LastKnown, SearchRadius, SearchStep)
+	}
+
+	// descartamos los puntos que ya visitamos
+	for len(e.SearchTargets) > 0 && e.NodePosition.Equal(e.SearchTargets[0]) {
+		e.SearchTargets = e.SearchTargets[1:]
+	}
+
+	if len(e.SearchTargets) == 0 {
+		// se termino la busqueda, nos rendimos
+		e.LastKnown = nil
+		e.Searching = false
+		return e.wander.Target(e)
+	}
+
+	return e.SearchTargets[0]
+}
@@ -15,6 +15,29 @@ const (
 	DirectionLeft
 )
 
+// Delta regresa el desplazamiento (x, y) en el mapa que corresponde a la direccion
+func (d Direction) Delta() (int, int) {
+	switch d {
+	case DirectionUp:
+		return 0, -1
+	case DirectionRight:
+		return 1, 0
+	case DirectionDown:
+		return 0, 1
+	case DirectionLeft:
+		return -1, 0
+	}
+	return 0, 0
+}
+
+// DirectionNames nombres de las direcciones en los archivos de datos
+var DirectionNames = map[string]Direction{
+	"arriba":    DirectionUp,
+	"derecha":   DirectionRight,
+	"abajo":     DirectionDown,
+	"izquierda": DirectionLeft,
+}
+
 type Player struct {
 	StayAnimation    *Animation
 	MovingAnimation  *Animation
@@ -24,15 +47,43 @@ type Player struct {
 	NodePosition     *Node
 	CurrentDirection Direction
 	Points           uint
+	Ajolotes         int // ajolotes recogidos, el juego termina al recogerlos todos
 	// valores para validar movimientos en el mapa
 	Mapa Maze
 	// apuntamos al padre
 	Game *Game
+	// de donde recibe las acciones de movimiento
+	Input InputSource
+	// direccion que pidio el jugador, se guarda hasta que se pueda tomar
+	DesiredDirection Direction
+	HasDesired       bool
+	// vidas restantes, al perder todas termina la partida
+	Lives     int
+	LivesUsed int
+	// donde reaparece al ser atrapado
+	Spawn             *Node
+	InvulnerableTicks int
+	Index             int            // numero de jugador, empieza en 0
+	Out               bool           // se quedo sin vidas, ya no juega
+	PowerUps          []PowerUpUsage // power-ups recogidos en la partida
+	Telemetry         PlayerTelemetry
+}
+
+// directionActions relaciona cada accion de movimiento con su direccion
+var directionActions = []struct {
+	Action    Action
+	Direction Direction
+}{
+	{ActionUp, DirectionUp},
+	{ActionDown, DirectionDown},
+	{ActionLeft, DirectionLeft},
+	{ActionRight, DirectionRight},
 }
 
 func NewPlayer() *Player {
 	return &Player{
 		CurrentDirection: DirectionDown,
+		Lives:            PlayerLives,
 	}
 }
 
@@ -45,8 +96,8 @@ func (player *Player) validNode(node *Node) bool {
 	f, c := player.Game.Dimensiones.Filas, player.Game.Dimensiones.Columnas
 	return (node.Y > 0 && node.Y < f) &&
 		(node.X > 0 && node.X < c) &&
-		// considerar los ajolotes pesos
-		(mapa[node.Y][node.X] == Transitable || mapa[node.Y][node.X] == AjolotePointType)
+		// considerar los ajolotes pesos y los power-ups
+		IsWalkableCell(mapa[node.Y][node.X])
 }
 
 func (player *Player) Moving() {
@@ -60,8 +111,13 @@ func (player *Player) Moving() {
 	dist := dir.SquaredDistance()
 
 	// Si hemos llegado al destino
-	// implica que la distancia entre ellos infima
-	if dist <= squaredMoveSpeed {
+	// implica que la distancia entre ellos infima,
+	// con el power-up de velocidad el margen crece en la misma proporcion que el paso
+	umbral := float64(squaredMoveSpeed)
+	if player.Game != nil && player.Game.Effects.Active(PowerUpSpeed) {
+		umbral *= player.Speed() / moveSpeed
+	}
+	if dist <= umbral {
 		player.CurrentPosition.X = player.TargetPosition.X
 		player.CurrentPosition.Y = player.TargetPosition.Y
 		player.IsMoving = false
@@ -71,17 +127,42 @@ func (player *Player) Moving() {
 	// Normalizamos para obtener la dirección unitaria
 	uni := dir.Normalize()
 	// Multiplicamos por la velocidad
-	movePlus := uni.MultiplyByScalar(moveSpeed)
+	movePlus := uni.MultiplyByScalar(player.Speed())
 
 	// ACTUALIZAR directamente las coordenadas, no crear nuevo vector
 	player.CurrentPosition.X += movePlus.X
 	player.CurrentPosition.Y += movePlus.Y
 }
 
-func (player *Player) Move(yMove, xMove int, direction Direction) {
+// Respawn regresa al jugador a su punto de aparicion con un tiempo de invulnerabilidad
+func (player *Player) Respawn() {
+	player.NodePosition = player.Spawn.Clone()
+	player.CurrentPosition = NewVector(float64(player.Spawn.X*squareSize), float64(player.Spawn.Y*squareSize))
+	player.TargetPosition = player.CurrentPosition.Clone()
+	player.IsMoving = false
+	player.HasDesired = false
+	player.CurrentDirection = DirectionDown
+	player.InvulnerableTicks = InvulnerableTicks
+}
+
+// IsInvulnerable indica si los enemigos todavia no lo pueden atrapar
+func (player *Player) IsInvulnerable() bool {
+	return player.InvulnerableTicks > 0
+}
+
+// Speed pixeles que avanza por tick, el power-up de velocidad la multiplica
+func (player *Player) Speed() float64 {
+	if player.Game != nil && player.Game.Effects.Active(PowerUpSpeed) {
+		return moveSpeed * PowerUpSpeedMultiplier
+	}
+	return moveSpeed
+}
+
+// Move regresa si el movimiento fue valido
+func (player *Player) Move(yMove, xMove int, direction Direction) bool {
 	// Si ya está moviéndose, no hacer nada
 	if player.IsMoving {
-		return
+		return false
 	}
 
 	// Clonamos el nodo para validar
@@ -98,23 +179,69 @@ func (player *Player) Move(yMove, xMove int, direction Direction) {
 		player.TargetPosition.X = float64(targetNode.X * squareSize)
 		player.TargetPosition.Y = float64(targetNode.Y * squareSize)
 		player.NodePosition = targetNode
+		return true
 	}
+	return false
 }
 
-func (player *Player) MoveToUp() {
-	player.Move(-1, 0, DirectionUp)
+// MoveInDirection intenta avanzar una celda en la direccion
+func (player *Player) MoveInDirection(d Direction) bool {
+	dx, dy := d.Delta()
+	return player.Move(dy, dx, d)
 }
 
-func (player *Player) MoveToDown() {
-	player.Move(1, 0, DirectionDown)
+// ReadDesiredDirection guarda la direccion que pide el jugador aunque se este moviendo,
+// una tecla recien presionada siempre reemplaza a la guardada
+func (player *Player) ReadDesiredDirection() {
+	for _, da := range directionActions {
+		if player.Input.JustPressed(da.Action) {
+			player.DesiredDirection = da.Direction
+			player.HasDesired = true
+			return
+		}
+	}
+
+	if player.HasDesired {
+		return
+	}
+
+	// mantener presionada una tecla la vuelve a pedir
+	for _, da := range directionActions {
+		if player.Input.Pressed(da.Action) {
+			player.DesiredDirection = da.Direction
+			player.HasDesired = true
+			return
+		}
+	}
+}
+
+// Steer decide el siguiente paso al llegar a una celda: primero la direccion guardada,
+// si aun no se puede tomar y autoRun esta activo sigue derecho hasta chocar con un muro
+func (player *Player) Steer(autoRun bool) {
+	if player.HasDesired && player.MoveInDirection(player.DesiredDirection) {
+		player.HasDesired = false
+		return
+	}
+
+	if autoRun {
+		player.MoveInDirection(player.CurrentDirection)
+	}
+}
+
+func (player *Player) MoveToUp() bool {
+	return player.Move(-1, 0, DirectionUp)
+}
+
+func (player *Player) MoveToDown() bool {
+	return player.Move(1, 0, DirectionDown)
 }
 
-func (player *Player) MoveToLeft() {
-	player.Move(0, -1, DirectionLeft)
+func (player *Player) MoveToLeft() bool {
+	return player.Move(0, -1, DirectionLeft)
 }
 
-func (player *Player) MoveToRight() {
-	player.Move(0, 1, DirectionRight)
+func (player *Player) MoveToRight() bool {
+	return player.Move(0, 1, DirectionRight)
 }
 
 func (player *Player) Tick() {
@@ -160,6 +287,16 @@ func (player *Player) DrawPlayer(screen *ebiten.Image) {
 	//// Creamos las opciones de transformación
 	imgOptions := &ebiten.DrawImageOptions{}
 
+	// el segundo jugador se distingue por su color
+	if player.Index == 1 {
+		imgOptions.ColorScale.Scale(0.6, 0.8, 1, 1)
+	}
+
+	// mientras es invulnerable parpadea
+	if player.IsInvulnerable() && (player.InvulnerableTicks/InvulnerableBlinkTicks)%2 == 0 {
+		imgOptions.ColorScale.ScaleAlpha(0.3)
+	}
+
 	playerFrame := player.GetSpriteFrame()
 	bounds := playerFrame.Bounds()
 	w := float64(bounds.Dx())
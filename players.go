@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// PlayMode cuantos jugadores hay y de que lado esta cada uno
+type PlayMode int
+
+const (
+	SoloMode   PlayMode = iota
+	CoopMode            // dos jugadores recogen ajolotes juntos
+	VersusMode          // el segundo jugador controla un perro
+)
+
+var playModeNames = map[PlayMode]string{
+	SoloMode:   "solo",
+	CoopMode:   "coop",
+	VersusMode: "versus",
+}
+
+func (m PlayMode) String() string {
+	return playModeNames[m]
+}
+
+// roles con los que se guarda el puntaje de cada jugador
+const (
+	RoleRunner = "jugador"
+	RoleDog    = "perro"
+)
+
+// NewMatchID identificador de partida, lo comparten los registros de todos los jugadores
+func NewMatchID() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 36)
+}
+
+// ActivePlayers jugadores que todavia tienen vidas
+func (j *Game) ActivePlayers() []*Player {
+	var activos []*Player
+	for _, p := range j.Players {
+		if !p.Out {
+			activos = append(activos, p)
+		}
+	}
+	return activos
+}
+
+// TotalAjolotes ajolotes recogidos entre todos los jugadores
+func (j *Game) TotalAjolotes() int {
+	total := 0
+	for _, p := range j.Players {
+		total += p.Ajolotes
+	}
+	return total
+}
+
+// TotalPoints puntaje de todos los jugadores que recorren el laberinto
+func (j *Game) TotalPoints() uint {
+	var total uint
+	for _, p := range j.Players {
+		total += p.Points
+	}
+	return total
+}
+
+// TargetPlayer regresa el jugador activo mas cercano por camino,
+// se calcula una vez por tick para no repetir la busqueda en cada comportamiento
+func (e *Enemy) TargetPlayer() *Player {
+	j := e.Juego
+	if e.target != nil && e.targetTick == j.Ticks && !e.target.Out {
+		return e.target
+	}
+
+	activos := j.ActivePlayers()
+	if len(activos) == 0 {
+		return j.Players[0]
+	}
+
+	mejor := activos[0]
+	if len(activos) > 1 {
+		campo := e.Maze().DistanceField(e.NodePosition)
+		mejorDistancia := math.MaxInt
+		for _, p := range activos {
+			d := campo[p.NodePosition.Y][p.NodePosition.X]
+			if d == -1 {
+				d = math.MaxInt - 1 // inalcanzable, solo si no hay otro
+			}
+			if d < mejorDistancia {
+				mejor, mejorDistancia = p, d
+			}
+		}
+	}
+
+	e.target, e.targetTick = mejor, j.Ticks
+	return mejor
+}
+
+// ControlledBehavior comportamiento del perro que maneja el segundo jugador en versus,
+// no tiene objetivo propio porque se mueve con las acciones del jugador
+type ControlledBehavior struct{}
+
+func (b *ControlledBehavior) Name() string {
+	return "controlado"
+}
+
+func (b *ControlledBehavior) Target(e *Enemy) *Node {
+	return e.NodePosition
+}
+
+// SpawnDog crea el perro del segundo jugador en el punto de aparicion mas lejano
+func (j *Game) SpawnDog(in InputSource) error {
+	tipo, err := GetArchetype(VersusDogArchetype)
+	if err != nil {
+		return err
+	}
+
+	puntos := j.Spawner.SafePoints(j)
+	if len(puntos) == 0 {
+		return fmt.Errorf("no hay donde colocar al perro del jugador")
+	}
+
+	e, err := j.NewEnemy(puntos[0].Clone(), tipo, j.Spawner.ElapseShift)
+	if err != nil {
+		return err
+	}
+	e.Behavior = &ControlledBehavior{}
+	e.Input = in
+	j.Dog = e
+	return nil
+}
+
+// Steer el perro de un jugador avanza una celda hacia donde indique su control,
+// regresa si se movio
+func (e *Enemy) Steer() bool {
+	for _, da := range directionActions {
+		if !e.Input.Pressed(da.Action) {
+			continue
+		}
+		dx, dy := da.Direction.Delta()
+		destino := NewNode(e.NodePosition.X+dx, e.NodePosition.Y+dy)
+		if !e.Maze().IsWalkable(destino.X, destino.Y) {
+			continue
+		}
+		e.NodePosition = destino
+		e.IsMoving = true
+		e.UpdateVectorTargetPosition()
+		return true
+	}
+	return false
+}
@@ -0,0 +1,511 @@
+package main
+
+import (
+	"encoding/gob"
+	"fmt"
+	"log"
+	"math"
+	"math/rand/v2"
+	"os"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// EnemyPolicy decide el siguiente movimiento de un enemigo,
+// regresa el indice del movimiento dentro de EnemyMoves
+type EnemyPolicy interface {
+	Name() string
+	Act(m Maze, enemigo, jugador *Node, dir Direction) int
+}
+
+// ==========================================
+// OBSERVACIONES
+// ==========================================
+
+// signo regresa -1, 0 o 1
+func signo(x int) int {
+	if x < 0 {
+		return -1
+	}
+	if x > 0 {
+		return 1
+	}
+	return 0
+}
+
+// ObserveState discretiza lo que ve el enemigo para la tabla Q:
+// direccion relativa al jugador (9), distancia (4), muros alrededor (16) y linea de vision (2)
+func ObserveState(m Maze, enemigo, jugador *Node) int {
+	dx := signo(jugador.X-enemigo.X) + 1
+	dy := signo(jugador.Y-enemigo.Y) + 1
+	relativa := dy*3 + dx
+
+	distancia := heuristica(enemigo, jugador)
+	var rango int
+	switch {
+	case distancia <= 2:
+		rango = 0
+	case distancia <= 5:
+		rango = 1
+	case distancia <= 10:
+		rango = 2
+	default:
+		rango = 3
+	}
+
+	muros := 0
+	for i, d := range []Pos{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+		if m.IsWalkable(enemigo.X+d.X, enemigo.Y+d.Y) {
+			muros |= 1 << i
+		}
+	}
+
+	vision := 0
+	if m.LineOfSight(enemigo, jugador) {
+		vision = 1
+	}
+
+	return ((relativa*4+rango)*16+muros)*2 + vision
+}
+
+// QStates numero de estados posibles de ObserveState
+const QStates = 9 * 4 * 16 * 2
+
+// DQNInputs numero de caracteristicas de ObserveFeatures
+const DQNInputs = 2 + 8 + 1 + 4
+
+// ObserveFeatures vector de caracteristicas para la red: posicion relativa del jugador,
+// celdas transitables alrededor, linea de vision y direccion del jugador (one hot)
+func ObserveFeatures(m Maze, enemigo, jugador *Node, dir Direction) []float64 {
+	f, c := m.GetShape()
+	x := make([]float64, 0, DQNInputs)
+
+	x = append(x,
+		float64(jugador.X-enemigo.X)/float64(c),
+		float64(jugador.Y-enemigo.Y)/float64(f),
+	)
+
+	for _, mov := range EnemyMoves {
+		if m.IsWalkable(enemigo.X+mov.X, enemigo.Y+mov.Y) {
+			x = append(x, 1)
+		} else {
+			x = append(x, 0)
+		}
+	}
+
+	if m.LineOfSight(enemigo, jugador) {
+		x = append(x, 1)
+	} else {
+		x = append(x, 0)
+	}
+
+	for d := DirectionUp; d <= DirectionLeft; d++ {
+		if d == dir {
+			x = append(x, 1)
+		} else {
+			x = append(x, 0)
+		}
+	}
+
+	return x
+}
+
+// ==========================================
+// A* (LINEA BASE)
+// ==========================================
+
+// AStarPolicy sigue la ruta de A*, es la politica con la que se comparan las aprendidas
+type AStarPolicy struct{}
+
+func (p *AStarPolicy) Name() string {
+	return "a_star"
+}
+
+func (p *AStarPolicy) Act(m Maze, enemigo, jugador *Node, dir Direction) int {
+	meta := AStart(m, enemigo.Clone(), jugador)
+	if meta == nil {
+		return 0
+	}
+	camino := meta.BuildWay()
+	if len(camino) < 2 {
+		return 0
+	}
+	return moveIndex(enemigo, camino[1])
+}
+
+// moveIndex regresa el indice en EnemyMoves para ir de un nodo a su vecino
+func moveIndex(desde, hacia *Node) int {
+	for i, mov := range EnemyMoves {
+		if desde.X+mov.X == hacia.X && desde.Y+mov.Y == hacia.Y {
+			return i
+		}
+	}
+	return 0
+}
+
+// ==========================================
+// Q-LEARNING TABULAR
+// ==========================================
+
+// QTable politica aprendida con Q-learning tabular
+type QTable struct {
+	Q [][]float64 // valor de cada (estado, accion)
+}
+
+func NewQTable() *QTable {
+	q := &QTable{Q: make([][]float64, QStates)}
+	for i := range q.Q {
+		q.Q[i] = make([]float64, len(EnemyMoves))
+	}
+	return q
+}
+
+func (q *QTable) Name() string {
+	return "q_learning"
+}
+
+func (q *QTable) Act(m Maze, enemigo, jugador *Node, dir Direction) int {
+	return argmax(q.Q[ObserveState(m, enemigo, jugador)])
+}
+
+// TrainQLearning entrena la tabla Q jugando partidas simuladas contra el jugador scriptado,
+// epsilon baja linealmente para pasar de explorar a explotar
+func TrainQLearning(episodios int, alpha, gamma float64) *QTable {
+	q := NewQTable()
+
+	for ep := 0; ep < episodios; ep++ {
+		epsilon := max(RLEpsilonMin, 1-float64(ep)/(float64(episodios)*RLEpsilonDecay))
+		sim := NewSimulation(SimFilas, SimColumnas)
+		estado := ObserveState(sim.Maze, sim.Enemy, sim.Player)
+
+		for {
+			accion := argmax(q.Q[estado])
+			if rand.Float64() < epsilon {
+				accion = rand.IntN(len(EnemyMoves))
+			}
+
+			recompensa, fin := sim.Step(accion)
+			siguiente := ObserveState(sim.Maze, sim.Enemy, sim.Player)
+
+			// Q(s,a) += alpha * (r + gamma * max Q(s',a') - Q(s,a))
+			objetivo := recompensa
+			if !fin {
+				objetivo += gamma * q.Q[siguiente][argmax(q.Q[siguiente])]
+			}
+			q.Q[estado][accion] += alpha * (objetivo - q.Q[estado][accion])
+
+			if fin {
+				break
+			}
+			estado = siguiente
+		}
+	}
+
+	return q
+}
+
+func argmax(v []float64) int {
+	mejor := 0
+	for i := 1; i < len(v); i++ {
+		if v[i] > v[mejor] {
+			mejor = i
+		}
+	}
+	return mejor
+}
+
+// ==========================================
+// DQN
+// ==========================================
+
+// DQN red pequeña (una capa oculta ReLU, salida lineal) que estima el valor Q de cada accion
+type DQN struct {
+	W1, B1 *mat.Dense
+	W2, B2 *mat.Dense
+}
+
+func NewDQN(nIn, nOcultas, nOut int) *DQN {
+	aleatoria := func(f, c int, escala float64) *mat.Dense {
+		data := make([]float64, f*c)
+		for i := range data {
+			data[i] = rand.NormFloat64() * escala
+		}
+		return mat.NewDense(f, c, data)
+	}
+
+	return &DQN{
+		W1: aleatoria(nIn, nOcultas, math.Sqrt(2/float64(nIn))), // inicializacion He para ReLU
+		B1: mat.NewDense(1, nOcultas, nil),
+		W2: aleatoria(nOcultas, nOut, math.Sqrt(1/float64(nOcultas))),
+		B2: mat.NewDense(1, nOut, nil),
+	}
+}
+
+func (d *DQN) Name() string {
+	return "dqn"
+}
+
+func (d *DQN) Act(m Maze, enemigo, jugador *Node, dir Direction) int {
+	x := ObserveFeatures(m, enemigo, jugador, dir)
+	_, q := d.Forward(mat.NewDense(1, len(x), x))
+	return argmax(q.RawRowView(0))
+}
+
+// Forward propaga un lote (muestras, caracteristicas), regresa la preactivacion oculta y los valores Q
+func (d *DQN) Forward(X *mat.Dense) (*mat.Dense, *mat.Dense) {
+	n, _ := X.Dims()
+	_, nOcultas := d.W1.Dims()
+	_, nOut := d.W2.Dims()
+
+	Z1 := mat.NewDense(n, nOcultas, nil)
+	Z1.Mul(X, d.W1)
+	sumarBias(Z1, d.B1)
+
+	H := mat.NewDense(n, nOcultas, nil)
+	H.Apply(func(i, j int, v float64) float64 {
+		return max(v, 0)
+	}, Z1)
+
+	Q := mat.NewDense(n, nOut, nil)
+	Q.Mul(H, d.W2)
+	sumarBias(Q, d.B2)
+
+	return Z1, Q
+}
+
+// Clone copia profunda de la red, se usa para la red objetivo
+func (d *DQN) Clone() *DQN {
+	return &DQN{
+		W1: mat.DenseCopyOf(d.W1),
+		B1: mat.DenseCopyOf(d.B1),
+		W2: mat.DenseCopyOf(d.W2),
+		B2: mat.DenseCopyOf(d.B2),
+	}
+}
+
+// sumarBias suma la fila de bias a cada fila de la matriz
+func sumarBias(m *mat.Dense, b *mat.Dense) {
+	m.Apply(func(i, j int, v float64) float64 {
+		return v + b.At(0, j)
+	}, m)
+}
+
+// Transition una experiencia guardada en la memoria de repeticion
+type Transition struct {
+	Estado     []float64
+	Accion     int
+	Recompensa float64
+	Siguiente  []float64
+	Fin        bool
+}
+
+// TrainBatch un paso de descenso de gradiente sobre un lote de experiencias,
+// el objetivo de cada una es r + gamma * max Q_objetivo(s'), solo se corrige la accion tomada
+func (d *DQN) TrainBatch(lote []Transition, objetivo *DQN, gamma, lr float64) {
+	n := len(lote)
+	nIn, nOcultas := d.W1.Dims()
+	_, nOut := d.W2.Dims()
+
+	X := mat.NewDense(n, nIn, nil)
+	X2 := mat.NewDense(n, nIn, nil)
+	for i, t := range lote {
+		X.SetRow(i, t.Estado)
+		X2.SetRow(i, t.Siguiente)
+	}
+
+	Z1, Q := d.Forward(X)
+	_, Q2 := objetivo.Forward(X2)
+
+	// dQ solo tiene error en la accion tomada (derivada del ECM)
+	dQ := mat.NewDense(n, nOut, nil)
+	for i, t := range lote {
+		y := t.Recompensa
+		if !t.Fin {
+			y += gamma * mat.Max(Q2.RowView(i))
+		}
+		err := Q.At(i, t.Accion) - y
+		// recortamos el error para estabilizar el entrenamiento
+		dQ.Set(i, t.Accion, max(-1, min(1, err))/float64(n))
+	}
+
+	H := mat.NewDense(n, nOcultas, nil)
+	H.Apply(func(i, j int, v float64) float64 {
+		return max(v, 0)
+	}, Z1)
+
+	dW2 := mat.NewDense(nOcultas, nOut, nil)
+	dW2.Mul(H.T(), dQ)
+
+	dH := mat.NewDense(n, nOcultas, nil)
+	dH.Mul(dQ, d.W2.T())
+	dH.Apply(func(i, j int, v float64) float64 {
+		if Z1.At(i, j) <= 0 {
+			return 0 // derivada de ReLU
+		}
+		return v
+	}, dH)
+
+	dW1 := mat.NewDense(nIn, nOcultas, nil)
+	dW1.Mul(X.T(), dH)
+
+	actualizar := func(W, dW *mat.Dense) {
+		dW.Scale(lr, dW)
+		W.Sub(W, dW)
+	}
+	actualizar(d.W2, dW2)
+	actualizar(d.W1, dW1)
+	actualizar(d.B2, sumaColumnas(dQ))
+	actualizar(d.B1, sumaColumnas(dH))
+}
+
+// sumaColumnas regresa una matriz fila con la suma de cada columna
+func sumaColumnas(m *mat.Dense) *mat.Dense {
+	_, c := m.Dims()
+	res := mat.NewDense(1, c, nil)
+	for j := 0; j < c; j++ {
+		res.Set(0, j, mat.Sum(m.ColView(j)))
+	}
+	return res
+}
+
+// TrainDQN entrena la red con memoria de repeticion y red objetivo
+// jugando partidas simuladas contra el jugador scriptado
+func TrainDQN(episodios int, gamma, lr float64) *DQN {
+	red := NewDQN(DQNInputs, DQNHidden, len(EnemyMoves))
+	objetivo := red.Clone()
+
+	memoria := make([]Transition, 0, DQNReplaySize)
+	siguienteLibre := 0
+	pasos := 0
+
+	for ep := 0; ep < episodios; ep++ {
+		epsilon := max(RLEpsilonMin, 1-float64(ep)/(float64(episodios)*RLEpsilonDecay))
+		sim := NewSimulation(SimFilas, SimColumnas)
+		estado := ObserveFeatures(sim.Maze, sim.Enemy, sim.Player, sim.PlayerDir)
+
+		for {
+			var accion int
+			if rand.Float64() < epsilon {
+				accion = rand.IntN(len(EnemyMoves))
+			} else {
+				accion = red.Act(sim.Maze, sim.Enemy, sim.Player, sim.PlayerDir)
+			}
+
+			recompensa, fin := sim.Step(accion)
+			siguiente := ObserveFeatures(sim.Maze, sim.Enemy, sim.Player, sim.PlayerDir)
+
+			t := Transition{estado, accion, recompensa, siguiente, fin}
+			// la memoria es circular, al llenarse se sobreescriben las experiencias mas viejas
+			if len(memoria) < DQNReplaySize {
+				memoria = append(memoria, t)
+			} else {
+				memoria[siguienteLibre] = t
+				siguienteLibre = (siguienteLibre + 1) % DQNReplaySize
+			}
+
+			pasos++
+			if len(memoria) >= DQNBatchSize && pasos%DQNTrainEvery == 0 {
+				lote := make([]Transition, DQNBatchSize)
+				for i := range lote {
+					lote[i] = memoria[rand.IntN(len(memoria))]
+				}
+				red.TrainBatch(lote, objetivo, gamma, lr)
+			}
+
+			if pasos%DQNTargetSync == 0 {
+				objetivo = red.Clone()
+			}
+
+			if fin {
+				break
+			}
+			estado = siguiente
+		}
+	}
+
+	return red
+}
+
+// ==========================================
+// PERSISTENCIA
+// ==========================================
+
+// GuardarPolitica guarda una politica aprendida (QTable o DQN) en un archivo .gob
+func GuardarPolitica(nombre string, p EnemyPolicy) error {
+	file, err := os.Create(nombre + ".gob")
+	if err != nil {
+		return fmt.Errorf("no se pudo crear el archivo: %v", err)
+	}
+	defer file.Close()
+
+	// guardamos la interfaz para poder cargar cualquier tipo de politica
+	if err = gob.NewEncoder(file).Encode(&p); err != nil {
+		return fmt.Errorf("error al codificar la politica: %v", err)
+	}
+	return nil
+}
+
+// CargarPolitica lee una politica guardada con GuardarPolitica
+func CargarPolitica(nombre string) (EnemyPolicy, error) {
+	file, err := os.Open(nombre + ".gob")
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo abrir el archivo: %v", err)
+	}
+	defer file.Close()
+
+	var p EnemyPolicy
+	if err = gob.NewDecoder(file).Decode(&p); err != nil {
+		return nil, fmt.Errorf("error al decodificar la politica: %v", err)
+	}
+	return p, nil
+}
+
+func init() {
+	gob.Register(&QTable{})
+	gob.Register(&DQN{})
+}
+
+// politicasCargadas evita leer el mismo archivo por cada enemigo
+var politicasCargadas = map[string]EnemyPolicy{}
+
+// ==========================================
+// COMPORTAMIENTO
+// ==========================================
+
+// LearnedBehavior mueve al enemigo con una politica aprendida, un paso a la vez
+type LearnedBehavior struct {
+	Policy EnemyPolicy
+	nombre string // nombre con el que se registro en EnemyBehaviors
+}
+
+// NewLearnedBehavior carga la politica del archivo, si no existe el enemigo persigue con A*
+func NewLearnedBehavior(nombre, archivo string) EnemyBehavior {
+	p, ok := politicasCargadas[archivo]
+	if !ok {
+		var err error
+		p, err = CargarPolitica(archivo)
+		if err != nil {
+			log.Printf("Advertencia: no se pudo cargar la politica %s, se usa persecucion: %v\n", archivo, err)
+			return &ChaseBehavior{}
+		}
+		politicasCargadas[archivo] = p
+	}
+	return &LearnedBehavior{Policy: p, nombre: nombre}
+}
+
+func (b *LearnedBehavior) Name() string {
+	return b.nombre
+}
+
+func (b *LearnedBehavior) Target(e *Enemy) *Node {
+	jugador := e.TargetPlayer()
+	maze := e.Maze()
+	mov := EnemyMoves[b.Policy.Act(maze, e.NodePosition, jugador.NodePosition, jugador.CurrentDirection)]
+	destino := NewNode(e.NodePosition.X+mov.X, e.NodePosition.Y+mov.Y)
+
+	// si la politica elige un muro, nos quedamos en el lugar
+	if !maze.IsWalkable(destino.X, destino.Y) {
+		return e.NodePosition
+	}
+	return destino
+}
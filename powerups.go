@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// PowerUp tipo de power-up, en el mapa se guarda como PowerUpFirstType + tipo
+type PowerUp int
+
+const (
+	PowerUpSpeed     PowerUp = iota // el jugador se mueve mas rapido
+	PowerUpFreeze                   // los enemigos dejan de avanzar
+	PowerUpInvisible                // los enemigos pierden el rastro y deambulan
+	PowerUpScare                    // los enemigos se asustan y huyen
+)
+
+// PowerUps todos los tipos, en el orden en que se muestran en el HUD
+var PowerUps = []PowerUp{PowerUpSpeed, PowerUpFreeze, PowerUpInvisible, PowerUpScare}
+
+var powerUpNames = map[PowerUp]string{
+	PowerUpSpeed:     "velocidad",
+	PowerUpFreeze:    "congelar",
+	PowerUpInvisible: "invisible",
+	PowerUpScare:     "susto",
+}
+
+// colores con los que se dibuja cada power-up en el mapa
+var powerUpColors = map[PowerUp]color.RGBA{
+	PowerUpSpeed:     {R: 255, G: 220, A: 255},
+	PowerUpFreeze:    {R: 120, G: 220, B: 255, A: 255},
+	PowerUpInvisible: {R: 200, G: 200, B: 200, A: 255},
+	PowerUpScare:     {R: 255, G: 80, B: 80, A: 255},
+}
+
+func (p PowerUp) String() string {
+	return powerUpNames[p]
+}
+
+// Cell valor de la celda del mapa que corresponde al power-up
+func (p PowerUp) Cell() int {
+	return PowerUpFirstType + int(p)
+}
+
+// Duration ticks que dura el efecto, el susto dura lo mismo que el modo asustado
+func (p PowerUp) Duration() int {
+	switch p {
+	case PowerUpSpeed:
+		return PowerUpSpeedTicks
+	case PowerUpFreeze:
+		return PowerUpFreezeTicks
+	case PowerUpInvisible:
+		return PowerUpInvisibleTicks
+	}
+	return FrightenedTicks
+}
+
+// PowerUpFromCell regresa el power-up que hay en la celda, si hay alguno
+func PowerUpFromCell(celda int) (PowerUp, bool) {
+	p := PowerUp(celda - PowerUpFirstType)
+	if _, ok := powerUpNames[p]; !ok {
+		return 0, false
+	}
+	return p, true
+}
+
+/*
+PlacePowerUps coloca power-ups de tipo aleatorio en caminos libres,
+nunca en las celdas reservadas (donde aparecen los jugadores y los enemigos)
+:return: cuantos se colocaron, menos que cantidad si no alcanzan los caminos libres
+*/
+func PlacePowerUps(mapa Maze, cantidad int, reservadas []*Node) int {
+	libres := slices.DeleteFunc(mapa.Cells(Transitable), func(n *Node) bool {
+		return slices.ContainsFunc(reservadas, n.Equal)
+	})
+	rand.Shuffle(len(libres), func(i, k int) {
+		libres[i], libres[k] = libres[k], libres[i]
+	})
+
+	colocados := min(cantidad, len(libres))
+	for _, n := range libres[:colocados] {
+		mapa.Set(n.X, n.Y, PowerUps[rand.IntN(len(PowerUps))].Cell())
+	}
+	return colocados
+}
+
+// PowerUpEffects ticks restantes de cada efecto activo
+type PowerUpEffects map[PowerUp]int
+
+// Active indica si el efecto sigue activo
+func (fx PowerUpEffects) Active(p PowerUp) bool {
+	return fx[p] > 0
+}
+
+// Activate inicia el efecto, si ya estaba activo se reinicia su duracion
+func (fx PowerUpEffects) Activate(p PowerUp) {
+	fx[p] = p.Duration()
+}
+
+// Tick descuenta un tick a cada efecto y quita los que terminaron
+func (fx PowerUpEffects) Tick() {
+	for p := range fx {
+		fx[p]--
+		if fx[p] <= 0 {
+			delete(fx, p)
+		}
+	}
+}
+
+// CollectPowerUp aplica el power-up que recogio el jugador y lo registra en su puntaje,
+// los efectos valen para todos los jugadores
+func (j *Game) CollectPowerUp(jugador *Player, p PowerUp) {
+	jugador.Points += PowerUpValue
+	j.Effects.Activate(p)
+
+	switch p {
+	case PowerUpScare:
+		j.FrightenEnemies()
+	case PowerUpInvisible:
+		// olvidan donde lo vieron por ultima vez, asi no van a buscarlo
+		for _, e := range j.Enemys {
+			e.LastKnown = nil
+			e.SearchTargets = nil
+			e.Searching = false
+		}
+	}
+
+	jugador.PowerUps = append(jugador.PowerUps, PowerUpUsage{
+		Kind:     p.String(),
+		At:       j.ElapsedSeconds(),
+		Duration: float64(p.Duration()) / TPS,
+		Score:    jugador.Points,
+	})
+}
+
+// DrawPowerUp dibuja el power-up como un circulo de su color sobre el piso
+func (j *Game) DrawPowerUp(screen *ebiten.Image, p PowerUp, x, y float64) {
+	centro := float32(squareSize) / 2
+	vector.FillCircle(screen, float32(x)+centro, float32(y)+centro, centro*0.6, powerUpColors[p], true)
+}
+
+// DrawEffects muestra los efectos activos con sus segundos restantes
+func (j *Game) DrawEffects(screen *ebiten.Image) {
+	if len(j.Effects) == 0 {
+		return
+	}
+
+	var partes []string
+	for _, p := range PowerUps {
+		if j.Effects.Active(p) {
+			partes = append(partes, fmt.Sprintf("%s %.1fs", p, float64(j.Effects[p])/TPS))
+		}
+	}
+
+	opciones := *j.Font.Options
+	opciones.GeoM.Translate(150, FontSize*2.5) // junto a las vidas
+	text.Draw(screen, "Efectos: "+strings.Join(partes, " | "), j.Font.Face, &opciones)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+// pasillo laberinto de 3 filas con un solo pasillo de largo celdas en medio
+func pasillo(largo int) Maze {
+	m := Maze{make([]int, largo+2), make([]int, largo+2), make([]int, largo+2)}
+	for x := range m[0] {
+		m[0][x], m[1][x], m[2][x] = 1, 1, 1
+	}
+	for x := 1; x <= largo; x++ {
+		m[1][x] = Transitable
+	}
+	return m
+}
+
+func TestPlacePowerUps(t *testing.T) {
+	casos := []struct {
+		nombre          string
+		largo, cantidad int
+		reservadas      []*Node
+		esperados       int
+	}{
+		{"sobran_caminos", 10, 3, nil, 3},
+		{"con_reservadas", 4, 3, []*Node{NewNode(1, 1), NewNode(4, 1)}, 2},
+		// menos caminos que power-ups, coloca los que caben y termina
+		{"faltan_caminos", 3, 8, nil, 3},
+		{"todo_reservado", 2, 1, []*Node{NewNode(1, 1), NewNode(2, 1)}, 0},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nombre, func(t *testing.T) {
+			m := pasillo(caso.largo)
+			if n := PlacePowerUps(m, caso.cantidad, caso.reservadas); n != caso.esperados {
+				t.Errorf("se colocaron %d, se esperaban %d", n, caso.esperados)
+			}
+
+			colocados := 0
+			for x := 1; x <= caso.largo; x++ {
+				if _, ok := PowerUpFromCell(m.Get(x, 1)); !ok {
+					continue
+				}
+				colocados++
+				if slices.ContainsFunc(caso.reservadas, NewNode(x, 1).Equal) {
+					t.Errorf("hay un power-up en la celda reservada (%d, 1)", x)
+				}
+			}
+			if colocados != caso.esperados {
+				t.Errorf("el mapa tiene %d power-ups, se esperaban %d", colocados, caso.esperados)
+			}
+		})
+	}
+}
+
+func TestPowerUpEffects(t *testing.T) {
+	rapido, congela := PowerUpSpeed.Duration(), PowerUpFreeze.Duration()
+	casos := []struct {
+		nombre       string
+		activaciones map[int][]PowerUp // tick -> power-ups que se toman en ese tick
+		ultimo       map[PowerUp]int   // ultimo tick en que el efecto sigue activo
+	}{
+		{"expira", map[int][]PowerUp{0: {PowerUpSpeed}}, map[PowerUp]int{PowerUpSpeed: rapido - 1}},
+		// tomarlo otra vez reinicia la duracion, no la suma
+		{"se_renueva", map[int][]PowerUp{0: {PowerUpSpeed}, 10: {PowerUpSpeed}}, map[PowerUp]int{PowerUpSpeed: 10 + rapido - 1}},
+		{"renovado_al_final", map[int][]PowerUp{0: {PowerUpSpeed}, rapido - 1: {PowerUpSpeed}}, map[PowerUp]int{PowerUpSpeed: 2*rapido - 2}},
+		{"independientes", map[int][]PowerUp{0: {PowerUpSpeed, PowerUpFreeze}}, map[PowerUp]int{PowerUpSpeed: rapido - 1, PowerUpFreeze: congela - 1}},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nombre, func(t *testing.T) {
+			fx := PowerUpEffects{}
+			for tick := 0; tick < 3*rapido; tick++ {
+				for _, p := range caso.activaciones[tick] {
+					fx.Activate(p)
+				}
+				for p, ultimo := range caso.ultimo {
+					if activo := tick <= ultimo; fx.Active(p) != activo {
+						t.Fatalf("tick %d: %s activo %v, se esperaba %v", tick, p, fx.Active(p), activo)
+					}
+				}
+				fx.Tick()
+			}
+			if len(fx) != 0 {
+				t.Errorf("quedaron efectos vencidos %v", fx)
+			}
+		})
+	}
+}
@@ -1,8 +1,14 @@
 package main
 
 import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
 	"math"
 	"math/rand"
+	"os"
+
+	"gonum.org/v1/gonum/mat"
 )
 
 type Vector []float64
@@ -62,11 +68,165 @@ func (v Vector) DesNormalizar(media, desviacion float64) Vector {
 	return desnormalizado
 }
 
-func Ridge(Yr Vector, X []Vector) {
-	m := float64(len(X)) // numero de filas (datos)
-	if m == 0 {
-		// regresar el errors
+// RidgeSolver como se calculan las betas
+type RidgeSolver string
+
+const (
+	RidgeClosedForm RidgeSolver = "cerrada"   // ecuaciones normales, exacto y rapido con pocas caracteristicas
+	RidgeGradient   RidgeSolver = "gradiente" // descenso de gradiente, el metodo original
+)
+
+// RidgeConfig parametros de entrenamiento de Ridge
+type RidgeConfig struct {
+	Solver    RidgeSolver
+	Lambda    float64 // penalizacion de las betas, b0 no se penaliza
+	Escalar   bool    // normaliza X y Y con Z-Score antes de ajustar
+	LR        float64 // solo para el descenso de gradiente
+	EpocasMax int
+	Epsilon   float64 // cambio minimo del costo para seguir iterando
+}
+
+func DefaultRidgeConfig() RidgeConfig {
+	return RidgeConfig{
+		Solver:    RidgeClosedForm,
+		Lambda:    0.0000001,
+		Escalar:   true,
+		LR:        0.0072,
+		EpocasMax: 150_000,
+		Epsilon:   0.000001,
+	}
+}
+
+// RidgeModel regresion lineal con penalizacion L2, Ym = b0 + bnXn
+type RidgeModel struct {
+	B0    float64
+	Betas Vector
+
+	Caracteristicas []string
+	// estadisticas del escalado, vacias si se ajusto con los datos tal cual
+	MediasX, DesviacionesX Vector
+	MediaY, DesviacionY    float64
+	Escalado               bool
+
+	Solver RidgeSolver
+	Lambda float64
+	Epocas int // iteraciones del descenso de gradiente, 0 con la forma cerrada
+}
+
+// validarDatos revisa que todas las filas tengan las mismas caracteristicas
+func validarDatos(Yr Vector, X []Vector) error {
+	if len(X) == 0 {
+		return fmt.Errorf("no hay datos para ajustar el modelo")
+	}
+	if len(Yr) != len(X) {
+		return fmt.Errorf("hay %d filas de X y %d valores de Y", len(X), len(Yr))
+	}
+	if len(X[0]) == 0 {
+		return fmt.Errorf("las filas no tienen caracteristicas")
+	}
+	for i, fx := range X {
+		if len(fx) != len(X[0]) {
+			return fmt.Errorf("la fila %d tiene %d caracteristicas, se esperaban %d", i, len(fx), len(X[0]))
+		}
+	}
+	return nil
+}
+
+// columna extrae la caracteristica j de todas las filas
+func columna(X []Vector, j int) Vector {
+	col := make(Vector, len(X))
+	for i, fx := range X {
+		col[i] = fx[j]
 	}
+	return col
+}
+
+/*
+Ridge ajusta b0 y las betas con la configuracion dada
+:param Yr: valores reales de cada fila
+:param X: filas de caracteristicas
+:return: modelo ajustado o error si los datos no sirven o no converge
+*/
+func Ridge(Yr Vector, X []Vector, cfg RidgeConfig) (*RidgeModel, error) {
+	if err := validarDatos(Yr, X); err != nil {
+		return nil, err
+	}
+	modelo := &RidgeModel{Solver: cfg.Solver, Lambda: cfg.Lambda, Escalado: cfg.Escalar}
+
+	// normalizamos cada columna por separado, las constantes quedan en cero
+	if cfg.Escalar {
+		columnas := make([]Vector, len(X[0]))
+		for j := range columnas {
+			var media, desviacion float64
+			columnas[j], media, desviacion = columna(X, j).Normalizar()
+			modelo.MediasX = append(modelo.MediasX, media)
+			modelo.DesviacionesX = append(modelo.DesviacionesX, desviacion)
+		}
+		// volvemos de columnas a filas
+		escaladas := make([]Vector, len(X))
+		for i := range escaladas {
+			escaladas[i] = columna(columnas, i)
+		}
+		X = escaladas
+		Yr, modelo.MediaY, modelo.DesviacionY = Yr.Normalizar()
+	}
+
+	var err error
+	switch cfg.Solver {
+	case RidgeClosedForm:
+		modelo.B0, modelo.Betas, err = ridgeCerrada(Yr, X, cfg.Lambda)
+	case RidgeGradient:
+		modelo.B0, modelo.Betas, modelo.Epocas, err = ridgeGradiente(Yr, X, cfg)
+	default:
+		err = fmt.Errorf("solver de ridge desconocido: %s", cfg.Solver)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return modelo, nil
+}
+
+/*
+ridgeCerrada resuelve las ecuaciones normales (AᵀA + m·λ·P) β = Aᵀy,
+A tiene una columna de unos para b0 y P es la identidad sin penalizar b0.
+Es el mismo minimo al que llega el descenso de gradiente
+*/
+func ridgeCerrada(Yr Vector, X []Vector, lambda float64) (float64, Vector, error) {
+	m, n := len(X), len(X[0])
+
+	A := mat.NewDense(m, n+1, nil)
+	for i, fx := range X {
+		A.Set(i, 0, 1)
+		for j, v := range fx {
+			A.Set(i, j+1, v)
+		}
+	}
+	y := mat.NewVecDense(m, Yr)
+
+	// el costo es 1/(2m) sum(err²) + λ/2 sum(β²), por eso la penalizacion se multiplica por m
+	var lhs mat.Dense
+	lhs.Mul(A.T(), A)
+	for j := 1; j <= n; j++ {
+		lhs.Set(j, j, lhs.At(j, j)+float64(m)*lambda)
+	}
+	var rhs mat.VecDense
+	rhs.MulVec(A.T(), y)
+
+	var beta mat.VecDense
+	if err := beta.SolveVec(&lhs, &rhs); err != nil {
+		return 0, nil, fmt.Errorf("no se pudo resolver ridge, las caracteristicas son linealmente dependientes (prueba con lambda mayor): %v", err)
+	}
+
+	betas := make(Vector, n)
+	for j := range betas {
+		betas[j] = beta.AtVec(j + 1)
+	}
+	return beta.AtVec(0), betas, nil
+}
+
+// ridgeGradiente el ajuste original por descenso de gradiente
+func ridgeGradiente(Yr Vector, X []Vector, cfg RidgeConfig) (float64, Vector, int, error) {
+	m := float64(len(X))            // numero de filas (datos)
 	numCaracteristicas := len(X[0]) // numero de caracteristicas columnas
 	betas := make(Vector, numCaracteristicas)
 
@@ -76,12 +236,11 @@ func Ridge(Yr Vector, X []Vector) {
 		betas[i] = rand.Float64()
 	}
 
-	epilon := 0.000001
-	MaximoEpocas := 150_000
+	epilon := cfg.Epsilon
+	MaximoEpocas := cfg.EpocasMax
 	epocas := 0
-	lr := 0.0072   // learning rate
-	T := 0.0000001 // lambda de penilizacion
-
+	lr := cfg.LR    // learning rate
+	T := cfg.Lambda // lambda de penilizacion
 	// J el nuestro error de modelo, tratamos de hacerlo lo mas chica posible
 	var Jactual float64 = 0
 	var Jant float64 = 10
@@ -138,6 +297,77 @@ func Ridge(Yr Vector, X []Vector) {
 			betas[jg] = betas[jg] - lr*((gradientes[jg]/m)+(T*betas[jg]))
 		}
 		epocas++
+
+		if math.IsNaN(Jactual) || math.IsInf(Jactual, 0) {
+			return 0, nil, epocas, fmt.Errorf("ridge diverge con lr %g, prueba uno mas chico o escalar los datos", lr)
+		}
 	}
 
+	return b0, betas, epocas, nil
+}
+
+// Predecir Ym = b0 + bnXn para una fila de caracteristicas sin escalar
+func (r *RidgeModel) Predecir(fx Vector) float64 {
+	Ym := r.B0
+	for j, valorX := range fx {
+		if r.Escalado {
+			// las columnas constantes se normalizaron a cero
+			if r.DesviacionesX[j] == 0 {
+				continue
+			}
+			valorX = (valorX - r.MediasX[j]) / r.DesviacionesX[j]
+		}
+		Ym += valorX * r.Betas[j]
+	}
+	if r.Escalado {
+		Ym = Vector{Ym}.DesNormalizar(r.MediaY, r.DesviacionY)[0]
+	}
+	return Ym
+}
+
+func (r *RidgeModel) Columnas() []string {
+	return r.Caracteristicas
+}
+
+// Lapsos predice cada fila de X, recortado al rango de lapsos del juego igual que la red
+func (r *RidgeModel) Lapsos(X *mat.Dense) []float64 {
+	filas, _ := X.Dims()
+	lapsos := make([]float64, filas)
+	for i := range lapsos {
+		lapsos[i] = max(EnemyElapseMin, min(EnemyElapseMax, r.Predecir(X.RawRowView(i))))
+	}
+	return lapsos
+}
+
+// GuardarRidge guarda el modelo con el mismo encabezado y checksum que las redes
+func GuardarRidge(nombreModelo string, r *RidgeModel) error {
+	if err := guardarArchivo(nombreModelo, RidgeFormatName, RidgeFormatVersion, r); err != nil {
+		return err
+	}
+	fmt.Println("El modelo ridge se guardo en disco: " + nombreModelo + ".gob !!!")
+	return nil
+}
+
+// CargarRidge lee un modelo ridge, las columnas que recibe son las caracteristicas que guarda
+func CargarRidge(nombreModelo string) (*RidgeModel, error) {
+	data, err := os.ReadFile(nombreModelo + ".gob")
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo abrir el archivo: %v", err)
+	}
+	contenido, _, err := abrirArchivo(data, RidgeFormatName, RidgeFormatVersion)
+	if err != nil {
+		return nil, err
+	}
+	var r RidgeModel
+	if err = gob.NewDecoder(bytes.NewReader(contenido)).Decode(&r); err != nil {
+		return nil, fmt.Errorf("error al decodificar el modelo: %v", err)
+	}
+	if len(r.Betas) != len(r.Caracteristicas) {
+		return nil, fmt.Errorf("el modelo tiene %d betas y %d caracteristicas", len(r.Betas), len(r.Caracteristicas))
+	}
+	if err = validarCaracteristicas(r.Caracteristicas); err != nil {
+		return nil, err
+	}
+	fmt.Printf("Modelo ridge '%s.gob' cargado correctamente.\n", nombreModelo)
+	return &r, nil
 }
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// datosLineales filas con y = 3 + 2*x1 - x2 mas ruido uniforme de amplitud ruido
+func datosLineales(n int, ruido float64) (Vector, []Vector) {
+	Yr := make(Vector, n)
+	X := make([]Vector, n)
+	for i := range X {
+		x1, x2 := rand.Float64()*10, rand.Float64()*100-50
+		X[i] = Vector{x1, x2}
+		Yr[i] = 3 + 2*x1 - x2 + ruido*(2*rand.Float64()-1)
+	}
+	return Yr, X
+}
+
+// la forma cerrada y el descenso de gradiente minimizan el mismo costo, deben llegar a las mismas betas
+func TestRidgeSolversCoinciden(t *testing.T) {
+	casos := []struct {
+		nombre        string
+		lambda, ruido float64
+		exacto        bool // sin ruido ni penalizacion se recupera y = 3 + 2*x1 - x2
+	}{
+		{"exacto", 0, 0, true},
+		{"ruido", 0.0000001, 5, false},
+		{"penalizado", 0.1, 5, false},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nombre, func(t *testing.T) {
+			Yr, X := datosLineales(60, caso.ruido)
+
+			cfg := DefaultRidgeConfig()
+			cfg.Lambda = caso.lambda
+			cerrada, err := Ridge(Yr, X, cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// epsilon chico para que el descenso llegue al minimo y no se quede a medio camino
+			cfg.Solver = RidgeGradient
+			cfg.LR = 0.1
+			cfg.Epsilon = 1e-14
+			gradiente, err := Ridge(Yr, X, cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gradiente.Epocas >= cfg.EpocasMax {
+				t.Fatalf("el descenso no convergio en %d epocas", gradiente.Epocas)
+			}
+
+			if math.Abs(cerrada.B0-gradiente.B0) > 1e-5 {
+				t.Errorf("b0: cerrada %g, gradiente %g", cerrada.B0, gradiente.B0)
+			}
+			for j := range cerrada.Betas {
+				if math.Abs(cerrada.Betas[j]-gradiente.Betas[j]) > 1e-5 {
+					t.Errorf("beta %d: cerrada %g, gradiente %g", j, cerrada.Betas[j], gradiente.Betas[j])
+				}
+			}
+
+			for _, fx := range []Vector{{0, 0}, {5, -20}, {10, 50}} {
+				c, g := cerrada.Predecir(fx), gradiente.Predecir(fx)
+				if math.Abs(c-g) > 1e-3 {
+					t.Errorf("%v: cerrada predice %g, gradiente %g", fx, c, g)
+				}
+				if esperado := 3 + 2*fx[0] - fx[1]; caso.exacto && math.Abs(c-esperado) > 1e-6 {
+					t.Errorf("%v: predice %g, se esperaba %g", fx, c, esperado)
+				}
+			}
+		})
+	}
+}
+
+// sin escalar las betas son los coeficientes de la recta tal cual
+func TestRidgeCerradaSinEscalar(t *testing.T) {
+	Yr, X := datosLineales(20, 0)
+	cfg := DefaultRidgeConfig()
+	cfg.Lambda = 0
+	cfg.Escalar = false
+	r, err := Ridge(Yr, X, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(r.B0-3) > 1e-9 || math.Abs(r.Betas[0]-2) > 1e-9 || math.Abs(r.Betas[1]+1) > 1e-9 {
+		t.Errorf("b0 %g y betas %v, se esperaba 3 y [2 -1]", r.B0, r.Betas)
+	}
+}
+
+func TestRidgeDatosInvalidos(t *testing.T) {
+	casos := []struct {
+		nombre string
+		Yr     Vector
+		X      []Vector
+	}{
+		{"vacio", nil, nil},
+		{"filas_distintas", Vector{1, 2}, []Vector{{1}}},
+		{"sin_caracteristicas", Vector{1}, []Vector{{}}},
+		{"columnas_distintas", Vector{1, 2}, []Vector{{1, 2}, {3}}},
+	}
+	for _, caso := range casos {
+		if _, err := Ridge(caso.Yr, caso.X, DefaultRidgeConfig()); err == nil {
+			t.Errorf("%s: se esperaba error", caso.nombre)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"log"
+)
+
+// RunRLTraining entrena las politicas de Q-learning y DQN en partidas simuladas,
+// las guarda junto a modelo_velocidad.gob y las compara contra A*
+func RunRLTraining() {
+	fmt.Println("Entrenando politica Q-learning...")
+	q := TrainQLearning(QEpisodes, QAlpha, RLGamma)
+	if err := GuardarPolitica(PolicyQFile, q); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Politica guardada en " + PolicyQFile + ".gob")
+
+	fmt.Println("Entrenando politica DQN...")
+	red := TrainDQN(DQNEpisodes, RLGamma, DQNLearningRate)
+	if err := GuardarPolitica(PolicyDQNFile, red); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Politica guardada en " + PolicyDQNFile + ".gob")
+
+	fmt.Printf("Evaluando en %d partidas simuladas...\n", RLEvalEpisodes)
+	for _, p := range []EnemyPolicy{&AStarPolicy{}, q, red} {
+		tasa, pasos := EvaluatePolicy(p, RLEvalEpisodes)
+		fmt.Printf("%-12s capturas: %5.1f%%  pasos promedio por captura: %.1f\n", p.Name(), tasa*100, pasos)
+	}
+}
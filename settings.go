@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// Settings configuracion del jugador, se guarda en SettingsFile
+type Settings struct {
+	Bindings    *Bindings `json:"bindings"`
+	BufferInput bool      `json:"buffer_input"` // guarda la direccion pedida hasta que se pueda tomar
+	AutoRun     bool      `json:"auto_run"`     // sigue avanzando en la direccion actual hasta chocar
+	// controles de cada jugador en cooperativo y versus, cada uno usa su propio control
+	TwoPlayerBindings [2]*Bindings `json:"two_player_bindings"`
+	// predictores de la velocidad inicial de los perros en el orden en que se prueban:
+	// mlp, ridge, media (de las ultimas partidas) o fija
+	SpeedPredictors []string `json:"speed_predictors"`
+	FixedElapse     int      `json:"fixed_elapse"` // lapso del predictor fija
+}
+
+func DefaultSettings() *Settings {
+	return &Settings{
+		Bindings:          DefaultBindings(),
+		BufferInput:       true,
+		TwoPlayerBindings: DefaultTwoPlayerBindings(),
+		SpeedPredictors:   []string{PredictorMLP, PredictorRidge, PredictorAverage, PredictorFixed},
+		FixedElapse:       EnemyElapseMax,
+	}
+}
+
+// LoadSettings lee la configuracion, si el archivo no existe regresa la configuracion por defecto
+func LoadSettings(path string) (*Settings, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return DefaultSettings(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo leer la configuracion: %v", err)
+	}
+
+	// decodificamos sobre la configuracion por defecto,
+	// asi lo que no venga en el archivo conserva su valor por defecto
+	s := DefaultSettings()
+	if err = json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("error al decodificar la configuracion: %v", err)
+	}
+	if s.Bindings == nil || s.Bindings.Actions == nil {
+		s.Bindings = DefaultBindings()
+	}
+	if len(s.SpeedPredictors) == 0 {
+		s.SpeedPredictors = DefaultSettings().SpeedPredictors
+	}
+	for i, b := range s.TwoPlayerBindings {
+		if b == nil || b.Actions == nil {
+			s.TwoPlayerBindings[i] = DefaultTwoPlayerBindings()[i]
+		}
+	}
+
+	return s, nil
+}
+
+// Save guarda la configuracion en formato JSON
+func (s *Settings) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error al codificar la configuracion: %v", err)
+	}
+	if err = os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("no se pudo guardar la configuracion: %v", err)
+	}
+	return nil
+}
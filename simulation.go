@@ -0,0 +1,186 @@
+package main
+
+import (
+	"math/rand/v2"
+)
+
+// Simulation es una partida simplificada sin graficos entre un enemigo y un jugador scriptado,
+// se usa para entrenar y evaluar politicas de movimiento de los enemigos.
+// Todo sucede a nivel celda: en cada paso el enemigo avanza una celda y el jugador
+// avanza segun su velocidad relativa
+type Simulation struct {
+	Maze        Maze
+	Player      *Node
+	Enemy       *Node
+	PlayerDir   Direction
+	PlayerSpeed float64 // celdas que avanza el jugador por cada paso del enemigo
+	Steps       int
+	MaxSteps    int
+	Ajolotes    int  // ajolotes que le quedan por recoger al jugador
+	Captured    bool // indica si el enemigo atrapo al jugador
+
+	playerAcc float64 // avance acumulado del jugador
+}
+
+// NewSimulation crea una partida con un laberinto nuevo, el jugador en la esquina
+// superior izquierda y el enemigo en una celda lejana aleatoria
+func NewSimulation(filas, columnas int) *Simulation {
+	m := NewMaze(columnas, filas)
+	Mazerand(m)
+
+	s := &Simulation{
+		Maze:        m,
+		Player:      NewNode(1, 1),
+		PlayerDir:   DirectionDown,
+		PlayerSpeed: SimPlayerSpeed,
+		MaxSteps:    SimMaxSteps,
+		Ajolotes:    len(m.Cells(AjolotePointType)),
+	}
+
+	distancias := m.DistanceField(s.Player)
+	for {
+		e := m.RandomWalkable()
+		if distancias[e.Y][e.X] >= SimSpawnDistance {
+			s.Enemy = e
+			break
+		}
+	}
+
+	return s
+}
+
+// Step mueve al enemigo segun la accion (indice de EnemyMoves) y despues al jugador.
+// Regresa la recompensa del enemigo y si la partida termino
+func (s *Simulation) Step(accion int) (float64, bool) {
+	s.Steps++
+	recompensa := SimStepReward
+
+	distancias := s.Maze.DistanceField(s.Player)
+	antes := distancias[s.Enemy.Y][s.Enemy.X]
+
+	anteriorEnemigo := s.Enemy
+	mov := EnemyMoves[accion]
+	destino := NewNode(s.Enemy.X+mov.X, s.Enemy.Y+mov.Y)
+	if s.Maze.IsWalkable(destino.X, destino.Y) {
+		s.Enemy = destino
+	} else {
+		recompensa += SimWallReward // chocar con un muro no sirve de nada
+	}
+
+	if s.Enemy.Equal(s.Player) {
+		s.Captured = true
+		return recompensa + SimCaptureReward, true
+	}
+
+	// premiamos acercarse al jugador
+	if despues := distancias[s.Enemy.Y][s.Enemy.X]; antes >= 0 && despues >= 0 {
+		recompensa += SimApproachReward * float64(antes-despues)
+	}
+
+	// el jugador puede avanzar varias celdas o ninguna segun su velocidad
+	s.playerAcc += s.PlayerSpeed
+	for s.playerAcc >= 1 {
+		s.playerAcc--
+		anteriorJugador := s.Player
+		s.movePlayer()
+
+		// se cruzaron o el jugador camino hacia el enemigo
+		if s.Player.Equal(s.Enemy) || (s.Player.Equal(anteriorEnemigo) && anteriorJugador.Equal(s.Enemy)) {
+			s.Captured = true
+			return recompensa + SimCaptureReward, true
+		}
+	}
+
+	if s.Steps >= s.MaxSteps || s.Ajolotes == 0 {
+		return recompensa, true
+	}
+
+	return recompensa, false
+}
+
+// movePlayer jugador scriptado: va por el ajolote mas cercano,
+// si el enemigo se acerca demasiado huye de el
+func (s *Simulation) movePlayer() {
+	dirs := []Direction{DirectionUp, DirectionRight, DirectionDown, DirectionLeft}
+
+	distEnemigo := s.Maze.DistanceField(s.Enemy)
+	peligro := distEnemigo[s.Player.Y][s.Player.X]
+
+	var campo [][]int
+	huir := peligro >= 0 && peligro <= SimDangerRadius
+	if !huir {
+		campo = s.Maze.MultiDistanceField(s.Maze.Cells(AjolotePointType))
+	}
+
+	mejor := -1
+	var mejorValor int
+	for _, d := range dirs {
+		dx, dy := d.Delta()
+		x, y := s.Player.X+dx, s.Player.Y+dy
+		if !s.Maze.IsWalkable(x, y) {
+			continue
+		}
+
+		var valor int
+		if huir {
+			valor = -distEnemigo[y][x] // entre mas lejos del enemigo mejor
+		} else {
+			valor = campo[y][x]
+		}
+
+		if mejor == -1 || valor < mejorValor {
+			mejor = int(d)
+			mejorValor = valor
+		}
+	}
+
+	if mejor == -1 {
+		return
+	}
+
+	// de vez en cuando se equivoca, asi el enemigo no aprende un solo recorrido
+	if rand.Float64() < SimPlayerNoise {
+		mejor = int(dirs[rand.IntN(len(dirs))])
+	}
+
+	dx, dy := Direction(mejor).Delta()
+	destino := NewNode(s.Player.X+dx, s.Player.Y+dy)
+	if !s.Maze.IsWalkable(destino.X, destino.Y) {
+		return
+	}
+
+	s.Player = destino
+	s.PlayerDir = Direction(mejor)
+
+	if s.Maze.Get(destino.X, destino.Y) == AjolotePointType {
+		s.Maze.Set(destino.X, destino.Y, Transitable)
+		s.Ajolotes--
+	}
+}
+
+// EvaluatePolicy juega varias partidas simuladas con la politica,
+// regresa la tasa de captura y el promedio de pasos de las capturas
+func EvaluatePolicy(p EnemyPolicy, partidas int) (float64, float64) {
+	capturas := 0
+	pasos := 0
+
+	for i := 0; i < partidas; i++ {
+		sim := NewSimulation(SimFilas, SimColumnas)
+		for {
+			if _, fin := sim.Step(p.Act(sim.Maze, sim.Enemy, sim.Player, sim.PlayerDir)); fin {
+				break
+			}
+		}
+		if sim.Captured {
+			capturas++
+			pasos += sim.Steps
+		}
+	}
+
+	promedioPasos := 0.0
+	if capturas > 0 {
+		promedioPasos = float64(pasos) / float64(capturas)
+	}
+
+	return float64(capturas) / float64(partidas), promedioPasos
+}
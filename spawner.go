@@ -0,0 +1,169 @@
+package main
+
+import "slices"
+
+// WaveEnemy describe a un enemigo dentro de una oleada
+type WaveEnemy struct {
+	Archetype    string // tipo de enemigo del registro EnemyArchetypes
+	ElapseOffset int    // ajuste al lapso base del tipo
+}
+
+// Wave es un grupo de enemigos que aparece a cierto tiempo o al alcanzar cierto puntaje,
+// lo que pase primero. Si no tiene ninguna condicion aparece al iniciar la partida
+type Wave struct {
+	AtTicks      int  // ticks de partida en que aparece, 0 no considera el tiempo
+	AtScore      uint // puntaje del jugador con el que aparece, 0 no considera el puntaje
+	ElapseOffset int  // ajuste de velocidad de toda la oleada
+	Enemies      []WaveEnemy
+}
+
+// Spawner agrega enemigos a la partida conforme avanzan el tiempo y el puntaje
+type Spawner struct {
+	Waves       []Wave
+	Next        int     // siguiente oleada por aparecer
+	TickCounter int     // ticks transcurridos de la partida
+	ElapseShift int     // ajuste de velocidad de la partida, segun la velocidad predicha
+	Points      []*Node // corrales y esquinas donde pueden aparecer enemigos
+	Corners     []*Node // esquinas a las que se dispersan los enemigos
+}
+
+// NewSpawner crea el generador de oleadas, la velocidad predicha desplaza
+// la velocidad base de todos los tipos de enemigo
+func NewSpawner(maze Maze, waves []Wave, predictedElapse int) *Spawner {
+	f, c := maze.GetShape()
+
+	esquinas := []*Node{
+		maze.NearestWalkable(c-2, f-2),
+		maze.NearestWalkable(c-2, 1),
+		maze.NearestWalkable(1, f-2),
+		maze.NearestWalkable(1, 1),
+	}
+
+	// el corral esta al centro del mapa, ademas de las esquinas
+	puntos := append([]*Node{maze.NearestWalkable(c/2, f/2)}, esquinas...)
+
+	return &Spawner{
+		Waves:       waves,
+		ElapseShift: predictedElapse - EnemyElapseMax,
+		Points:      puntos,
+		Corners:     esquinas,
+	}
+}
+
+// Tick revisa si ya toca la siguiente oleada
+func (s *Spawner) Tick(j *Game) error {
+	s.TickCounter++
+
+	if s.Next >= len(s.Waves) {
+		return nil
+	}
+
+	w := s.Waves[s.Next]
+	porTiempo := w.AtTicks > 0 && s.TickCounter >= w.AtTicks
+	porPuntaje := w.AtScore > 0 && j.TotalPoints() >= w.AtScore
+	inmediata := w.AtTicks == 0 && w.AtScore == 0
+
+	if !porTiempo && !porPuntaje && !inmediata {
+		return nil
+	}
+
+	puntos := s.SafePoints(j)
+	if len(puntos) == 0 {
+		return nil // no hay lugar seguro, lo volvemos a intentar el siguiente tick
+	}
+
+	for i, we := range w.Enemies {
+		tipo, err := GetArchetype(we.Archetype)
+		if err != nil {
+			return err
+		}
+		spawn := puntos[i%len(puntos)]
+		e, err := j.NewEnemy(spawn.Clone(), tipo, s.ElapseShift+w.ElapseOffset+we.ElapseOffset)
+		if err != nil {
+			return err
+		}
+		e.HomeCorner = s.homeCorner(spawn, len(j.Enemys))
+	}
+
+	s.Next++
+	return nil
+}
+
+// SafePoints regresa los puntos de aparicion lejos de los jugadores, del mas lejano al mas cercano.
+// Nunca regresa un punto sobre un jugador o junto a el
+func (s *Spawner) SafePoints(j *Game) []*Node {
+	var jugadores []*Node
+	for _, p := range j.ActivePlayers() {
+		jugadores = append(jugadores, p.NodePosition)
+	}
+	distancias := j.Maze.MultiDistanceField(jugadores)
+
+	junto := func(p *Node) bool {
+		for _, n := range jugadores {
+			if heuristica(p, n) <= 1 {
+				return true
+			}
+		}
+		return false
+	}
+
+	var seguros []*Node
+	for _, p := range s.Points {
+		d := distancias[p.Y][p.X]
+		// inalcanzable tambien es seguro
+		if d == -1 || (d >= SpawnSafeDistance && !junto(p)) {
+			seguros = append(seguros, p)
+		}
+	}
+
+	lejania := func(p *Node) int {
+		if d := distancias[p.Y][p.X]; d >= 0 {
+			return d
+		}
+		return len(distancias) * len(distancias[0])
+	}
+	slices.SortFunc(seguros, func(a, b *Node) int {
+		return lejania(b) - lejania(a)
+	})
+
+	return seguros
+}
+
+// homeCorner si aparece en una esquina se dispersa hacia ella, si no se reparten las esquinas
+func (s *Spawner) homeCorner(spawn *Node, n int) *Node {
+	for _, esquina := range s.Corners {
+		if esquina.Equal(spawn) {
+			return esquina.Clone()
+		}
+	}
+	return s.Corners[n%len(s.Corners)].Clone()
+}
+
+// DefaultWaves oleadas de la partida: tres perros con personalidades distintas
+// y refuerzos conforme pasa el tiempo o el jugador junta puntos, el lobo usa la politica dqn
+var DefaultWaves = []Wave{
+	{
+		Enemies: []WaveEnemy{
+			{Archetype: "perro"},
+			{Archetype: "sabueso"},
+			{Archetype: "guardian"},
+		},
+	},
+	{
+		AtTicks: 60 * TPS,
+		AtScore: MaxAjolotePoints / 3,
+		Enemies: []WaveEnemy{
+			{Archetype: "callejero"},
+		},
+	},
+	{
+		AtTicks:      120 * TPS,
+		AtScore:      MaxAjolotePoints * 2 / 3,
+		ElapseOffset: -10,
+		Enemies: []WaveEnemy{
+			{Archetype: "excavador"},
+			// sin politica entrenada persigue con A*
+			{Archetype: "lobo"},
+		},
+	},
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"gonum.org/v1/gonum/mat"
+	"gorm.io/gorm"
+)
+
+// nombres de los predictores que se pueden poner en speed_predictors
+const (
+	PredictorMLP     = SpeedModelMLP
+	PredictorRidge   = SpeedModelRidge
+	PredictorAverage = "media"
+	PredictorFixed   = "fija"
+)
+
+// SpeedPredictor decide el lapso inicial de los perros a partir de las partidas anteriores
+type SpeedPredictor interface {
+	Name() string
+	// Predict historial va de la partida mas reciente a la mas vieja
+	Predict(historial []GameScore) (int, error)
+}
+
+// ModelPredictor usa un modelo entrenado con la ultima partida como entrada,
+// cada modelo trae las caracteristicas con las que se entreno
+type ModelPredictor struct {
+	nombre  string
+	archivo string
+	cargar  func() (ModeloLapso, error)
+}
+
+func NewMLPPredictor() *ModelPredictor {
+	return &ModelPredictor{
+		nombre:  PredictorMLP,
+		archivo: VelocityModelFile,
+		cargar: func() (ModeloLapso, error) {
+			return CargarModeloVelocidad()
+		},
+	}
+}
+
+func NewRidgePredictor() *ModelPredictor {
+	return &ModelPredictor{
+		nombre:  PredictorRidge,
+		archivo: RidgeModelFile,
+		cargar: func() (ModeloLapso, error) {
+			return CargarRidge(RidgeModelFile)
+		},
+	}
+}
+
+func (p *ModelPredictor) Name() string {
+	return p.nombre
+}
+
+func (p *ModelPredictor) Predict(historial []GameScore) (int, error) {
+	if len(historial) == 0 {
+		return 0, fmt.Errorf("no hay partidas previas")
+	}
+	// Verificar si existe el modelo
+	if _, err := os.Stat(p.archivo + ".gob"); err != nil {
+		return 0, fmt.Errorf("no hay modelo entrenado")
+	}
+	modelo, err := p.cargar()
+	if err != nil {
+		return 0, err
+	}
+
+	// Nota: El modelo trae sus escaladores, recibe los datos crudos y regresa el lapso
+	fila, err := FilaCaracteristicas(historial[0], modelo.Columnas())
+	if err != nil {
+		return 0, err
+	}
+	input := mat.NewDense(1, len(fila), fila)
+	return int(modelo.Lapsos(input)[0]), nil
+}
+
+// AveragePredictor promedio de los lapsos finales de las ultimas partidas,
+// los perros empiezan a la velocidad a la que terminaron en promedio
+type AveragePredictor struct {
+	Ventana int // partidas que se promedian
+}
+
+func (p AveragePredictor) Name() string {
+	return PredictorAverage
+}
+
+func (p AveragePredictor) Predict(historial []GameScore) (int, error) {
+	if len(historial) == 0 {
+		return 0, fmt.Errorf("no hay partidas previas")
+	}
+	partidas := historial[:min(p.Ventana, len(historial))]
+	suma := 0
+	for _, s := range partidas {
+		suma += s.Velocity
+	}
+	return suma / len(partidas), nil
+}
+
+// FixedPredictor siempre el mismo lapso, nunca falla asi que va al final de la cadena
+type FixedPredictor struct {
+	Elapse int
+}
+
+func (p FixedPredictor) Name() string {
+	return PredictorFixed
+}
+
+func (p FixedPredictor) Predict(historial []GameScore) (int, error) {
+	return p.Elapse, nil
+}
+
+// NewSpeedPredictor crea un predictor por su nombre
+func NewSpeedPredictor(nombre string, settings *Settings) (SpeedPredictor, error) {
+	switch nombre {
+	case PredictorMLP:
+		return NewMLPPredictor(), nil
+	case PredictorRidge:
+		return NewRidgePredictor(), nil
+	case PredictorAverage:
+		return AveragePredictor{Ventana: SpeedAverageWindow}, nil
+	case PredictorFixed:
+		return FixedPredictor{Elapse: settings.FixedElapse}, nil
+	}
+	return nil, fmt.Errorf("predictor de velocidad desconocido: %s", nombre)
+}
+
+// SpeedPredictorChain prueba cada predictor en orden hasta que uno funcione
+type SpeedPredictorChain []SpeedPredictor
+
+func (c SpeedPredictorChain) Name() string {
+	return "cadena"
+}
+
+func (c SpeedPredictorChain) Predict(historial []GameScore) (int, error) {
+	for _, p := range c {
+		elapse, err := p.Predict(historial)
+		if err != nil {
+			log.Printf("Advertencia: el predictor de velocidad %s fallo, se usa el siguiente: %v\n", p.Name(), err)
+			continue
+		}
+		log.Printf("Velocidad inicial de los perros con el predictor %s: %d\n", p.Name(), elapse)
+		return elapse, nil
+	}
+	return 0, fmt.Errorf("ningun predictor de velocidad funciono")
+}
+
+// NewSpeedPredictorChain arma la cadena con los predictores de la configuracion,
+// un nombre desconocido se avisa y se salta como si hubiera fallado
+func NewSpeedPredictorChain(settings *Settings) SpeedPredictorChain {
+	var cadena SpeedPredictorChain
+	for _, nombre := range settings.SpeedPredictors {
+		p, err := NewSpeedPredictor(nombre, settings)
+		if err != nil {
+			log.Printf("Advertencia: %v\n", err)
+			continue
+		}
+		cadena = append(cadena, p)
+	}
+	return cadena
+}
+
+// PredictElapse predice la velocidad inicial de los enemigos con los predictores de la configuracion
+// a partir de las ultimas partidas, si todos fallan usa la velocidad por defecto
+func PredictElapse(db *gorm.DB, settings *Settings, noPredict bool) int {
+	predictedElapse := EnemyElapseMax // Valor por defecto (Lento)
+
+	if noPredict {
+		return predictedElapse
+	}
+
+	cadena := NewSpeedPredictorChain(settings)
+
+	// Obtener últimos puntajes, del mas reciente al mas viejo
+	var historial []GameScore
+	if err := RunnerScores(db).Preload("Telemetry").Order("created_at desc").Limit(SpeedHistoryGames).Find(&historial).Error; err != nil {
+		log.Printf("Advertencia: no se pudieron leer las partidas previas: %v\n", err)
+	}
+
+	elapse, err := cadena.Predict(historial)
+	if err != nil {
+		log.Printf("Advertencia: %v, usando velocidad por defecto.\n", err)
+		return predictedElapse
+	}
+	return max(EnemyElapseMin, min(EnemyElapseMax, elapse))
+}
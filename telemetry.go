@@ -0,0 +1,158 @@
+package main
+
+import "gorm.io/gorm"
+
+// MatchTelemetry como jugo la partida cada jugador, se guarda junto a su GameScore
+type MatchTelemetry struct {
+	gorm.Model
+	GameScoreID       uint      `json:"game_score_id"`
+	PlayedSeconds     float64   `json:"played_seconds"` // tiempo que jugo antes de quedarse sin vidas
+	AjolotesPerMinute float64   `json:"ajolotes_per_minute"`
+	NearMisses        int       `json:"near_misses"`      // veces que un perro llego a NearMissCells pasos sin atraparlo
+	AvgDogDistance    float64   `json:"avg_dog_distance"` // pasos promedio al perro mas cercano
+	DirectionChanges  int       `json:"direction_changes"`
+	IdleSeconds       float64   `json:"idle_seconds"` // tiempo sin moverse
+	Maze              MazeStats `json:"maze" gorm:"embedded;embeddedPrefix:maze_"`
+}
+
+// MazeStats forma del laberinto de la partida
+type MazeStats struct {
+	WalkableCells int `json:"walkable_cells"`
+	DeadEnds      int `json:"dead_ends"`     // celdas con una sola salida
+	Junctions     int `json:"junctions"`     // celdas con tres o cuatro salidas
+	FarthestCell  int `json:"farthest_cell"` // pasos desde el inicio hasta la celda mas lejana
+}
+
+// DeathEvent donde y como atraparon al jugador
+type DeathEvent struct {
+	gorm.Model
+	GameScoreID uint    `json:"game_score_id"`
+	At          float64 `json:"at"` // segundos desde que empezo la partida
+	X           int     `json:"x"`
+	Y           int     `json:"y"`
+	Archetype   string  `json:"archetype"`  // tipo del perro que lo atrapo
+	Behavior    string  `json:"behavior"`   // comportamiento del perro
+	EnemyMode   string  `json:"enemy_mode"` // persecucion o dispersion
+	ByPlayer    bool    `json:"by_player"`  // lo atrapo el perro del segundo jugador
+	Score       uint    `json:"score"`      // puntaje al ser atrapado
+}
+
+// PlayerTelemetry acumula la telemetria de un jugador mientras juega
+type PlayerTelemetry struct {
+	Ticks            int // ticks que jugo
+	IdleTicks        int
+	DirectionChanges int
+	NearMisses       int
+	sumaDistancia    float64
+	ticksDistancia   int // ticks con algun perro alcanzable
+	enPeligro        bool
+	direccion        Direction
+	conDireccion     bool // ya se movio desde que aparecio
+	Deaths           []DeathEvent
+}
+
+// NewMazeStats cuenta callejones y cruces, se calcula al crear la partida
+// porque la habilidad rompe_muros quita paredes mientras se juega
+func NewMazeStats(m Maze, inicio *Node) MazeStats {
+	var s MazeStats
+	for y, fila := range m {
+		for x := range fila {
+			if !m.IsWalkable(x, y) {
+				continue
+			}
+			s.WalkableCells++
+			switch salidas := m.OpenNeighbors(x, y); {
+			case salidas == 1:
+				s.DeadEnds++
+			case salidas >= 3:
+				s.Junctions++
+			}
+		}
+	}
+	for _, fila := range m.DistanceField(inicio) {
+		for _, d := range fila {
+			s.FarthestCell = max(s.FarthestCell, d)
+		}
+	}
+	return s
+}
+
+// RecordTelemetry registra un tick de cada jugador activo,
+// se llama despues de mover a todos y antes de revisar las colisiones
+func (j *Game) RecordTelemetry() {
+	// un perro asustado no puede atrapar, pasar junto a el no es salvarse
+	var perros, peligrosos []*Node
+	for _, e := range j.Enemys {
+		if e.IsSpawning() {
+			continue
+		}
+		perros = append(perros, e.NodePosition)
+		if e.Mode() != FrightenedMode {
+			peligrosos = append(peligrosos, e.NodePosition)
+		}
+	}
+	campo := j.Maze.MultiDistanceField(perros)
+	campoPeligro := j.Maze.MultiDistanceField(peligrosos)
+
+	for _, p := range j.ActivePlayers() {
+		t := &p.Telemetry
+		t.Ticks++
+		if !p.IsMoving {
+			t.IdleTicks++
+		} else {
+			if t.conDireccion && p.CurrentDirection != t.direccion {
+				t.DirectionChanges++
+			}
+			t.direccion, t.conDireccion = p.CurrentDirection, true
+		}
+
+		if d := campo[p.NodePosition.Y][p.NodePosition.X]; d >= 0 {
+			t.sumaDistancia += float64(d)
+			t.ticksDistancia++
+		}
+
+		// se cuenta al alejarse, si lo atrapan no fue un escape
+		d := campoPeligro[p.NodePosition.Y][p.NodePosition.X]
+		if d >= 0 && d <= NearMissCells && !p.IsInvulnerable() {
+			t.enPeligro = true
+		} else if t.enPeligro {
+			t.NearMisses++
+			t.enPeligro = false
+		}
+	}
+}
+
+// RecordDeath guarda donde y por quien fue atrapado el jugador
+func (j *Game) RecordDeath(p *Player, e *Enemy) {
+	t := &p.Telemetry
+	t.Deaths = append(t.Deaths, DeathEvent{
+		At:        j.ElapsedSeconds(),
+		X:         p.NodePosition.X,
+		Y:         p.NodePosition.Y,
+		Archetype: e.Archetype.Name,
+		Behavior:  e.Behavior.Name(),
+		EnemyMode: e.Mode().String(),
+		ByPlayer:  e == j.Dog,
+		Score:     p.Points,
+	})
+	// al reaparecer empieza de nuevo
+	t.enPeligro, t.conDireccion = false, false
+}
+
+// Resumen telemetria que se guarda al terminar la partida
+func (t *PlayerTelemetry) Resumen(ajolotes int, laberinto MazeStats) *MatchTelemetry {
+	m := &MatchTelemetry{
+		PlayedSeconds:    float64(t.Ticks) / TPS,
+		NearMisses:       t.NearMisses,
+		DirectionChanges: t.DirectionChanges,
+		IdleSeconds:      float64(t.IdleTicks) / TPS,
+		Maze:             laberinto,
+	}
+	if m.PlayedSeconds > 0 {
+		m.AjolotesPerMinute = float64(ajolotes) / (m.PlayedSeconds / 60)
+	}
+	if t.ticksDistancia > 0 {
+		m.AvgDogDistance = t.sumaDistancia / float64(t.ticksDistancia)
+	}
+	return m
+}
@@ -3,11 +3,327 @@ package main
 import (
 	"fmt"
 	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"gonum.org/v1/gonum/mat"
 )
 
-func RunTraining() {
+// TrainOptions parametros del entrenamiento que se pueden cambiar desde la linea de comandos
+type TrainOptions struct {
+	Optimizador string
+	AjusteLR    string
+	LR          float64
+	L2          float64
+	TamLote     int
+	Epocas      int
+	Validacion  float64 // fraccion de los registros para decidir cuando detenerse
+	Prueba      float64 // fraccion de los registros que solo se usa para medir
+	Paciencia   int     // epocas sin mejorar antes de detenerse
+	KFold       int     // pliegues de la validacion cruzada, 0 no la hace
+	Modelo      string  // mlp o ridge
+	Ridge       RidgeConfig
+	// columnas que recibe el modelo, nil las elige SeleccionarCaracteristicas
+	Caracteristicas []string
+	// el modelo es de jugadores humanos, las partidas del bot y las cortadas solo se usan si se piden
+	Bot       bool // usa las partidas del bot
+	Truncadas bool // usa las partidas que se cortaron por tiempo
+}
+
+func DefaultTrainOptions() TrainOptions {
+	return TrainOptions{
+		Optimizador: "adam",
+		AjusteLR:    "coseno",
+		LR:          0.05,
+		TamLote:     MLPBatchSize,
+		Epocas:      5000,
+		Validacion:  ValidationFraction,
+		Prueba:      TestFraction,
+		Paciencia:   EarlyStoppingPatience,
+		Modelo:      SpeedModelMLP,
+		Ridge:       DefaultRidgeConfig(),
+	}
+}
+
+// ParseArg lee un argumento --nombre=valor del comando train,
+// regresa false si el argumento no es de entrenamiento
+func (o *TrainOptions) ParseArg(arg string) (bool, error) {
+	if !strings.HasPrefix(arg, "--") {
+		return false, nil
+	}
+	nombre, valor, ok := strings.Cut(arg[2:], "=")
+	if !ok {
+		return false, nil
+	}
+
+	var err error
+	switch nombre {
+	case "optimizador":
+		_, err = OptimizerByName(valor)
+		o.Optimizador = valor
+	case "ajuste-lr":
+		_, err = ScheduleByName(valor, 1)
+		o.AjusteLR = valor
+	case "lr":
+		o.LR, err = strconv.ParseFloat(valor, 64)
+	case "l2":
+		o.L2, err = strconv.ParseFloat(valor, 64)
+	case "lote":
+		o.TamLote, err = positivo(valor)
+	case "epocas":
+		o.Epocas, err = positivo(valor)
+	case "validacion":
+		o.Validacion, err = fraccion(valor)
+	case "prueba":
+		o.Prueba, err = fraccion(valor)
+	case "paciencia":
+		o.Paciencia, err = strconv.Atoi(valor)
+	case "kfold":
+		o.KFold, err = strconv.Atoi(valor)
+	case "modelo":
+		if valor != SpeedModelMLP && valor != SpeedModelRidge {
+			err = fmt.Errorf("modelo desconocido: %s", valor)
+		}
+		o.Modelo = valor
+	case "lambda":
+		o.Ridge.Lambda, err = strconv.ParseFloat(valor, 64)
+	case "solver":
+		o.Ridge.Solver = RidgeSolver(valor)
+		if o.Ridge.Solver != RidgeClosedForm && o.Ridge.Solver != RidgeGradient {
+			err = fmt.Errorf("solver desconocido: %s", valor)
+		}
+	case "escalar":
+		o.Ridge.Escalar, err = strconv.ParseBool(valor)
+	case "caracteristicas":
+		o.Caracteristicas, err = parseCaracteristicas(valor)
+	case "bot":
+		o.Bot, err = strconv.ParseBool(valor)
+	case "truncadas":
+		o.Truncadas, err = strconv.ParseBool(valor)
+	default:
+		return false, nil
+	}
+	if err == nil && o.Validacion+o.Prueba >= 1 {
+		err = fmt.Errorf("validacion y prueba dejan sin datos al entrenamiento")
+	}
+	if err != nil {
+		return true, fmt.Errorf("valor invalido para --%s: %v", nombre, err)
+	}
+	return true, nil
+}
+
+// parseCaracteristicas lista separada por comas, auto deja que se seleccionen al entrenar
+func parseCaracteristicas(valor string) ([]string, error) {
+	if valor == SelectionAuto {
+		return nil, nil
+	}
+	nombres := strings.Split(valor, ",")
+	for _, nombre := range nombres {
+		if _, err := CaracteristicaPorNombre(nombre); err != nil {
+			return nil, fmt.Errorf("%v, las disponibles son %s", err, strings.Join(NombresCaracteristicas(), ", "))
+		}
+	}
+	return nombres, nil
+}
+
+func positivo(valor string) (int, error) {
+	n, err := strconv.Atoi(valor)
+	if err == nil && n <= 0 {
+		err = fmt.Errorf("debe ser mayor a cero")
+	}
+	return n, err
+}
+
+// VelocityFeatures columnas del modelo de velocidad original, las de los archivos de la version 1
+// y las que se usan cuando no hay suficientes partidas con telemetria para seleccionar
+var VelocityFeatures = []string{"score", "time"}
+
+// CargarModeloVelocidad carga el modelo de velocidad, los archivos de la version 1
+// no guardaban como se escalaba la salida: si es sigmoide se predice en 0..1 y hay que deshacer el MinMax
+func CargarModeloVelocidad() (*Red, error) {
+	red, err := CargarRed(VelocityModelFile)
+	if err != nil {
+		return nil, err
+	}
+	if red.Caracteristicas == nil {
+		if r, _ := red.W[0].Dims(); r != len(VelocityFeatures) {
+			return nil, fmt.Errorf("el modelo recibe %d caracteristicas y se le dieron %d", r, len(VelocityFeatures))
+		}
+		red.Caracteristicas = VelocityFeatures
+		if red.Salida == nil && red.Activaciones[len(red.Activaciones)-1].Name() == Sigmoid.Name() {
+			// velocity = val * (Max - Min) + Min
+			red.Salida = MinMax([]float64{EnemyElapseMin}, []float64{EnemyElapseMax})
+		}
+	}
+	return red, nil
+}
+
+// ModeloLapso modelo que predice el lapso de los enemigos a partir de las caracteristicas de una partida
+type ModeloLapso interface {
+	// Lapsos lapso en ticks para cada fila de X, dentro del rango que usa el juego
+	Lapsos(X *mat.Dense) []float64
+	// Columnas caracteristicas que recibe en cada fila, en orden
+	Columnas() []string
+}
+
+func (red *Red) Columnas() []string {
+	return red.Caracteristicas
+}
+
+// Lapsos lapso en ticks que predice la red para cada fila de X
+func (red *Red) Lapsos(X *mat.Dense) []float64 {
+	salida := red.Predecir(X)
+	r, _ := salida.Dims()
+	lapsos := make([]float64, r)
+	for i := range lapsos {
+		// el juego nunca usa un lapso fuera de este rango, asi se mide lo que vera el jugador
+		lapsos[i] = max(EnemyElapseMin, min(EnemyElapseMax, salida.At(i, 0)))
+	}
+	return lapsos
+}
+
+// EvaluarVelocidad metricas del modelo sobre una particion, en ticks
+func EvaluarVelocidad(m ModeloLapso, p Particion) Metricas {
+	return CalcularMetricas(mat.Col(nil, 0, p.Y), m.Lapsos(p.X))
+}
+
+/*
+ajustarVelocidad entrena el modelo de velocidad con los datos crudos de entrenamiento,
+la validacion (si no esta vacia) decide cuando detenerse
+:return: red que acepta datos crudos y regresa el lapso, historial de la perdida
+*/
+func ajustarVelocidad(entrenamiento, validacion Particion, opciones TrainOptions) (*Red, error) {
+	// con muy pocas partidas la separacion de validacion y prueba puede dejar vacio el entrenamiento
+	if entrenamiento.Filas() < MinTrainingSamples {
+		return nil, fmt.Errorf("quedaron %d registros para entrenar, se necesitan al menos %d (baja --validacion y --prueba o juega mas partidas)",
+			entrenamiento.Filas(), MinTrainingSamples)
+	}
+
+	// 1. Normalizar datos de Entrada (X) y de salida (Yd) con Z-Score, solo con las estadisticas de entrenamiento
+	X_norm, media, desviacion := NormalizarZScore(entrenamiento.X)
+	Yd_norm, mediaY, desviacionY := NormalizarZScore(entrenamiento.Y)
+
+	entrada, salida := ZScore(media, desviacion), ZScore(mediaY, desviacionY)
+
+	// la validacion se normaliza con las mismas estadisticas para no filtrar informacion al entrenamiento
+	var val *Particion
+	if validacion.Filas() > 0 {
+		val = &Particion{
+			X: entrada.Normalizar(validacion.X),
+			Y: salida.Normalizar(validacion.Y),
+		}
+	}
+
+	// 2. Configurar red, el optimizador guarda estado asi que se crea uno por entrenamiento
+	optimizador, err := OptimizerByName(opciones.Optimizador)
+	if err != nil {
+		return nil, err
+	}
+	ajuste, err := ScheduleByName(opciones.AjusteLR, opciones.Epocas)
+	if err != nil {
+		return nil, err
+	}
+
+	_, numFeatures := entrenamiento.X.Dims()
+	// la salida es lineal en lugar de sigmoide, asi no hay que desnormalizar con MinMax al predecir
+	config := ConfigRed{
+		Entradas: numFeatures,
+		Capas: []Capa{
+			{Neuronas: 4, Activacion: Tanh}, // Capa oculta con 4 neuronas
+			{Neuronas: 1, Activacion: Linear},
+		},
+		Perdida:     MSELoss{},
+		LR:          opciones.LR,
+		EpocasMax:   opciones.Epocas,
+		TamLote:     opciones.TamLote,
+		Optimizador: optimizador,
+		AjusteLR:    ajuste,
+		L2:          opciones.L2,
+		Validacion:  val,
+		Paciencia:   opciones.Paciencia,
+	}
+
+	red, historial := EntrenarRed(X_norm, Yd_norm, config)
+	if historial.MejorEpoca >= len(historial.Entrenamiento) {
+		return nil, fmt.Errorf("el entrenamiento termino sin completar ninguna epoca")
+	}
+
+	// 3. Guardamos los escaladores para que el modelo acepte inputs crudos y regrese el lapso en su escala
+	red.Caracteristicas = opciones.Caracteristicas
+	red.Entrada, red.Salida = entrada, salida
+	red.Info = InfoEntrenamiento{
+		Fecha:        time.Now(),
+		Muestras:     entrenamiento.Filas(),
+		Epocas:       len(historial.Entrenamiento),
+		MejorEpoca:   historial.MejorEpoca,
+		Optimizador:  optimizador.Name(),
+		AjusteLR:     ajuste.Name(),
+		LR:           opciones.LR,
+		TamLote:      opciones.TamLote,
+		L2:           opciones.L2,
+		Perdida:      config.Perdida.Name(),
+		PerdidaFinal: historial.Entrenamiento[historial.MejorEpoca], // la de los pesos que se regresaron
+	}
+	if historial.MejorEpoca < len(historial.Validacion) {
+		red.Info.PerdidaValidacion = historial.Validacion[historial.MejorEpoca]
+	}
+	return red, nil
+}
+
+// ajustarRidge ajusta ridge con las filas de entrenamiento, no necesita validacion
+func ajustarRidge(entrenamiento Particion, cfg RidgeConfig, caracteristicas []string) (*RidgeModel, error) {
+	filas := entrenamiento.Filas()
+	X := make([]Vector, filas)
+	for i := range X {
+		X[i] = Vector(mat.Row(nil, i, entrenamiento.X))
+	}
+	modelo, err := Ridge(mat.Col(nil, 0, entrenamiento.Y), X, cfg)
+	if err != nil {
+		return nil, err
+	}
+	modelo.Caracteristicas = caracteristicas
+	return modelo, nil
+}
+
+// ajustarModelo entrena el modelo elegido en las opciones
+func ajustarModelo(entrenamiento, validacion Particion, opciones TrainOptions) (ModeloLapso, error) {
+	if opciones.Modelo == SpeedModelRidge {
+		return ajustarRidge(entrenamiento, opciones.Ridge, opciones.Caracteristicas)
+	}
+	return ajustarVelocidad(entrenamiento, validacion, opciones)
+}
+
+// validacionCruzada entrena un modelo por pliegue y reporta las metricas de las muestras que dejo fuera
+func validacionCruzada(X, Yd *mat.Dense, opciones TrainOptions) {
+	fmt.Printf("Validacion cruzada con %d pliegues...\n", opciones.KFold)
+	var metricas []Metricas
+	for f, pliegue := range Pliegues(X, Yd, opciones.KFold) {
+		// dentro del pliegue separamos la validacion para el early stopping
+		entrenamiento, validacion, _ := DividirDatos(pliegue.Entrenamiento.X, pliegue.Entrenamiento.Y, opciones.Validacion, 0)
+		modelo, err := ajustarModelo(entrenamiento, validacion, opciones)
+		if err != nil {
+			log.Fatal(err)
+		}
+		m := EvaluarVelocidad(modelo, pliegue.Prueba)
+		metricas = append(metricas, m)
+		fmt.Printf("  pliegue %d: %s\n", f+1, m)
+	}
+	media, desviacion := PromediarMetricas(metricas)
+	fmt.Printf("Promedio: MAE %.2f ± %.2f, RMSE %.2f ± %.2f, R² %.3f ± %.3f\n",
+		media.MAE, desviacion.MAE, media.RMSE, desviacion.RMSE, media.R2, desviacion.R2)
+}
+
+func fraccion(valor string) (float64, error) {
+	f, err := strconv.ParseFloat(valor, 64)
+	if err == nil && (f < 0 || f >= 1) {
+		err = fmt.Errorf("debe estar entre 0 y 1")
+	}
+	return f, err
+}
+
+func RunTraining(opciones TrainOptions) {
 	fmt.Println("Iniciando proceso de entrenamiento...")
 
 	// 1. Abrir Base de Datos
@@ -16,9 +332,21 @@ func RunTraining() {
 		log.Fatalf("Error al abrir la base de datos: %v", err)
 	}
 
-	// 2. Extraer datos
+	if err = MigrateDB(db); err != nil {
+		log.Fatalf("Error al actualizar la base de datos: %v", err)
+	}
+
+	// 2. Extraer datos, con la telemetria de cada partida
 	var scores []GameScore
-	result := db.Find(&scores)
+	consulta := RunnerScores(db)
+	if !opciones.Bot {
+		consulta = consulta.Where("bot = ?", false)
+	}
+	// una partida cortada no llego al final, su velocidad no es la de una partida completa
+	if !opciones.Truncadas {
+		consulta = consulta.Where("truncada = ?", false)
+	}
+	result := consulta.Preload("Telemetry").Find(&scores)
 	if result.Error != nil {
 		log.Fatalf("Error al leer datos de la BD: %v", result.Error)
 	}
@@ -29,62 +357,88 @@ func RunTraining() {
 
 	fmt.Printf("Se encontraron %d registros para entrenamiento.\n", len(scores))
 
-	// 3. Construir matrices X (Inputs) y Yd (Target)
-	// Inputs: Score, Time
-	// Target: Velocity
-	numSamples := len(scores)
-	numFeatures := 2 // Score, Time
-
-	dataX := make([]float64, numSamples*numFeatures)
-	dataY := make([]float64, numSamples)
-
-	for i, s := range scores {
-		// X: [Score, Time]
-		dataX[i*numFeatures+0] = float64(s.Score)
-		dataX[i*numFeatures+1] = s.Time
-
-		// Yd: [Velocity]
-		// Normalizamos MinMax (0-1) para que coincida con Sigmoide
-		// Velocity (Elapse) va de Min=30 (Rápido) a Max=80 (Lento)
-		// dataY[i] = float64(s.Velocity)
-		normY := (float64(s.Velocity) - float64(EnemyElapseMin)) / float64(EnemyElapseMax-EnemyElapseMin)
-		if normY < 0 {
-			normY = 0
-		}
-		if normY > 1 {
-			normY = 1
+	// 3. Separar entrenamiento, validacion y prueba antes de elegir columnas,
+	// si la seleccion viera la prueba sus metricas saldrian optimistas
+	partidasEnt, partidasVal, partidasPrueba := DividirPartidas(scores, opciones.Validacion, opciones.Prueba)
+
+	// 4. Elegir las caracteristicas, si no se indicaron se seleccionan entre todas las candidatas
+	// solo con el entrenamiento, la seleccion hace sus propios pliegues dentro de el
+	if opciones.Caracteristicas == nil {
+		seleccion, err := SeleccionarCaracteristicas(partidasEnt, NombresCaracteristicas(), SelectionFolds)
+		if err != nil {
+			fmt.Printf("No se pudieron seleccionar caracteristicas (%v), se usan %s\n", err, strings.Join(VelocityFeatures, ", "))
+			seleccion = VelocityFeatures
 		}
-		dataY[i] = normY
+		opciones.Caracteristicas = seleccion
 	}
+	fmt.Printf("Caracteristicas: %s\n", strings.Join(opciones.Caracteristicas, ", "))
 
-	X := mat.NewDense(numSamples, numFeatures, dataX)
-	Yd := mat.NewDense(numSamples, 1, dataY)
-
-	// 4. Normalizar datos de Entrada (X) con Z-Score
-	fmt.Println("Normalizando datos...")
-	X_norm, media, desviacion := NormalizarZScore(X)
-
-	// Yd ya está normalizado a 0-1 manualmente
+	// Inputs: las caracteristicas elegidas
+	// Target: Velocity
+	entrenamiento, saltadasEnt := ParticionCaracteristicas(partidasEnt, opciones.Caracteristicas)
+	validacion, saltadasVal := ParticionCaracteristicas(partidasVal, opciones.Caracteristicas)
+	prueba, saltadasPrueba := ParticionCaracteristicas(partidasPrueba, opciones.Caracteristicas)
+	if saltadas := saltadasEnt + saltadasVal + saltadasPrueba; saltadas > 0 {
+		fmt.Printf("Se saltaron %d registros que no tienen todas las caracteristicas.\n", saltadas)
+	}
+	if entrenamiento.Filas() == 0 {
+		log.Fatal("Ningun registro de entrenamiento tiene las caracteristicas elegidas.")
+	}
+	fmt.Printf("Entrenamiento: %d, validacion: %d, prueba: %d\n", entrenamiento.Filas(), validacion.Filas(), prueba.Filas())
 
-	// 5. Configurar red
-	n_in := 2
-	n_out := 1
-	n_layers := []int{4} // Capa oculta con 4 neuronas
-	lr := 0.01
-	epochs := 50000
+	// 5. Validacion cruzada, solo para reportar que tan bien generaliza, la prueba queda fuera
+	if opciones.KFold > 1 {
+		X, Yd, _ := MatrizCaracteristicas(slices.Concat(partidasEnt, partidasVal), opciones.Caracteristicas)
+		if numSamples, _ := X.Dims(); opciones.KFold > numSamples {
+			log.Fatalf("no hay suficientes registros para %d pliegues", opciones.KFold)
+		}
+		validacionCruzada(X, Yd, opciones)
+	}
 
-	fmt.Println("Entrenando modelo...")
-	W, B, ecm := Entrenar(X_norm, Yd, n_in, n_out, n_layers, lr, epochs)
+	if opciones.Modelo == SpeedModelRidge {
+		fmt.Printf("Ajustando ridge (%s), lambda %g...\n", opciones.Ridge.Solver, opciones.Ridge.Lambda)
+	} else {
+		fmt.Printf("Entrenando modelo con %s, tasa %g (%s), lote %d, %d epocas...\n",
+			opciones.Optimizador, opciones.LR, opciones.AjusteLR, opciones.TamLote, opciones.Epocas)
+	}
+	modelo, err := ajustarModelo(entrenamiento, validacion, opciones)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	if len(ecm) > 0 {
-		fmt.Printf("Error final: %f\n", ecm[len(ecm)-1])
+	switch m := modelo.(type) {
+	case *Red:
+		fmt.Printf("Error final: %f\n", m.Info.PerdidaFinal)
+		if validacion.Filas() > 0 {
+			fmt.Printf("Se entreno %d epocas, se usan los pesos de la epoca %d (perdida de validacion %f)\n",
+				m.Info.Epocas, m.Info.MejorEpoca, m.Info.PerdidaValidacion)
+		}
+	case *RidgeModel:
+		fmt.Printf("b0 %f, betas %v\n", m.B0, m.Betas)
 	}
 
-	// 6. Desnormalizar para que el modelo acepte inputs crudos
-	// Integramos la normalización en la primera capa
-	W, B = Desnormalizar(W, B, media, desviacion)
+	// 6. Metricas en ticks de lapso, las de prueba son las que importan y se guardan con el modelo
+	for _, p := range []struct {
+		nombre string
+		datos  Particion
+	}{{"entrenamiento", entrenamiento}, {"validacion", validacion}, {"prueba", prueba}} {
+		if p.datos.Filas() > 0 {
+			fmt.Printf("%-14s %s\n", p.nombre+":", EvaluarVelocidad(modelo, p.datos))
+		}
+	}
 
 	// 7. Guardar modelo
-	GuardarModelo("modelo_velocidad", W, B)
+	switch m := modelo.(type) {
+	case *Red:
+		if prueba.Filas() > 0 {
+			m.Info.Prueba = EvaluarVelocidad(m, prueba)
+		}
+		err = GuardarRed(VelocityModelFile, m)
+	case *RidgeModel:
+		err = GuardarRidge(RidgeModelFile, m)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
 	fmt.Println("Entrenamiento finalizado.")
 }
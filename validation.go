@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Particion subconjunto de las muestras, X y Y tienen las mismas filas
+type Particion struct {
+	X, Y *mat.Dense
+}
+
+// Filas numero de muestras, una particion vacia tiene X nil
+func (p Particion) Filas() int {
+	if p.X == nil {
+		return 0
+	}
+	r, _ := p.X.Dims()
+	return r
+}
+
+// tomarFilas copia las filas indicadas de X y Yd a una particion nueva
+func tomarFilas(X, Yd *mat.Dense, indices []int) Particion {
+	if len(indices) == 0 {
+		return Particion{}
+	}
+	_, cx := X.Dims()
+	_, cy := Yd.Dims()
+	p := Particion{
+		X: mat.NewDense(len(indices), cx, nil),
+		Y: mat.NewDense(len(indices), cy, nil),
+	}
+	for k, i := range indices {
+		p.X.SetRow(k, X.RawRowView(i))
+		p.Y.SetRow(k, Yd.RawRowView(i))
+	}
+	return p
+}
+
+/*
+DividirDatos separa las muestras al azar en entrenamiento, validacion y prueba
+:param validacion, prueba: fraccion de las muestras que va a cada particion
+:return: particiones de entrenamiento, validacion y prueba, las dos ultimas pueden quedar vacias
+*/
+func DividirDatos(X, Yd *mat.Dense, validacion, prueba float64) (Particion, Particion, Particion) {
+	n, _ := X.Dims()
+	entrenamiento, val, test := ordenParticiones(n, validacion, prueba)
+	return tomarFilas(X, Yd, entrenamiento), tomarFilas(X, Yd, val), tomarFilas(X, Yd, test)
+}
+
+// ordenParticiones reparte al azar los indices de n muestras en entrenamiento, validacion y prueba
+func ordenParticiones(n int, validacion, prueba float64) ([]int, []int, []int) {
+	orden := rand.Perm(n)
+	nPrueba := int(float64(n) * prueba)
+	nValidacion := int(float64(n) * validacion)
+	return orden[nPrueba+nValidacion:], orden[nPrueba : nPrueba+nValidacion], orden[:nPrueba]
+}
+
+// Pliegue una vuelta de la validacion cruzada
+type Pliegue struct {
+	Entrenamiento Particion
+	Prueba        Particion
+}
+
+// Pliegues divide las muestras en k grupos, cada pliegue deja un grupo fuera para probar
+func Pliegues(X, Yd *mat.Dense, k int) []Pliegue {
+	n, _ := X.Dims()
+	orden := rand.Perm(n)
+	pliegues := make([]Pliegue, 0, k)
+	for f := 0; f < k; f++ {
+		inicio, fin := f*n/k, (f+1)*n/k
+		resto := append(append([]int{}, orden[:inicio]...), orden[fin:]...)
+		pliegues = append(pliegues, Pliegue{
+			Entrenamiento: tomarFilas(X, Yd, resto),
+			Prueba:        tomarFilas(X, Yd, orden[inicio:fin]),
+		})
+	}
+	return pliegues
+}
+
+// Metricas errores de una regresion en las unidades de la salida
+type Metricas struct {
+	MAE  float64 // error absoluto promedio
+	RMSE float64 // raiz del error cuadratico promedio
+	R2   float64 // fraccion de la varianza que explica el modelo, 1 es perfecto
+}
+
+// CalcularMetricas compara los valores deseados con los predichos
+func CalcularMetricas(deseados, predichos []float64) Metricas {
+	n := float64(len(deseados))
+	media := 0.0
+	for _, d := range deseados {
+		media += d
+	}
+	media /= n
+
+	var absoluto, cuadrado, varianza float64
+	for i, d := range deseados {
+		e := d - predichos[i]
+		absoluto += math.Abs(e)
+		cuadrado += e * e
+		varianza += (d - media) * (d - media)
+	}
+
+	m := Metricas{
+		MAE:  absoluto / n,
+		RMSE: math.Sqrt(cuadrado / n),
+		R2:   math.NaN(), // sin varianza no se puede calcular
+	}
+	if varianza > 0 {
+		m.R2 = 1 - cuadrado/varianza
+	}
+	return m
+}
+
+func (m Metricas) String() string {
+	return fmt.Sprintf("MAE %.2f, RMSE %.2f, R² %.3f", m.MAE, m.RMSE, m.R2)
+}
+
+// PromediarMetricas promedio y desviacion de las metricas de varios pliegues
+func PromediarMetricas(lista []Metricas) (Metricas, Metricas) {
+	var media, desviacion Metricas
+	n := float64(len(lista))
+	for _, m := range lista {
+		media.MAE += m.MAE / n
+		media.RMSE += m.RMSE / n
+		media.R2 += m.R2 / n
+	}
+	for _, m := range lista {
+		desviacion.MAE += (m.MAE - media.MAE) * (m.MAE - media.MAE) / n
+		desviacion.RMSE += (m.RMSE - media.RMSE) * (m.RMSE - media.RMSE) / n
+		desviacion.R2 += (m.R2 - media.R2) * (m.R2 - media.R2) / n
+	}
+	desviacion.MAE = math.Sqrt(desviacion.MAE)
+	desviacion.RMSE = math.Sqrt(desviacion.RMSE)
+	desviacion.R2 = math.Sqrt(desviacion.R2)
+	return media, desviacion
+}
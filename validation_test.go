@@ -0,0 +1,128 @@
+package main
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// datosNumerados la fila i tiene i en X y 10*i en Y, asi se puede rastrear cada muestra
+func datosNumerados(n int) (*mat.Dense, *mat.Dense) {
+	if n == 0 {
+		// gonum no permite matrices de cero filas, la tabla vacia es una matriz sin datos
+		return &mat.Dense{}, &mat.Dense{}
+	}
+	X := mat.NewDense(n, 2, nil)
+	Yd := mat.NewDense(n, 1, nil)
+	for i := 0; i < n; i++ {
+		X.Set(i, 0, float64(i))
+		X.Set(i, 1, float64(-i))
+		Yd.Set(i, 0, float64(10*i))
+	}
+	return X, Yd
+}
+
+// filasDe numeros de las muestras de una particion, revisa que X y Y sigan juntas
+func filasDe(t *testing.T, p Particion) []int {
+	t.Helper()
+	var filas []int
+	for i := 0; i < p.Filas(); i++ {
+		n := p.X.At(i, 0)
+		if p.X.At(i, 1) != -n || p.Y.At(i, 0) != 10*n {
+			t.Fatalf("la fila %d se separo de su salida", i)
+		}
+		filas = append(filas, int(n))
+	}
+	return filas
+}
+
+func TestDividirDatos(t *testing.T) {
+	casos := []struct {
+		filas                    int
+		validacion, prueba       float64
+		entrenamiento, val, test int
+	}{
+		{0, ValidationFraction, TestFraction, 0, 0, 0},
+		{1, ValidationFraction, TestFraction, 1, 0, 0},
+		{1, 0.5, 0.4, 1, 0, 0},
+		{20, ValidationFraction, TestFraction, 14, 3, 3},
+		{100, ValidationFraction, TestFraction, 70, 15, 15},
+		{100, 0, 0, 100, 0, 0},
+		{10, 0.5, 0.4, 1, 5, 4},
+	}
+	for _, c := range casos {
+		X, Yd := datosNumerados(c.filas)
+		entrenamiento, validacion, prueba := DividirDatos(X, Yd, c.validacion, c.prueba)
+		if entrenamiento.Filas() != c.entrenamiento || validacion.Filas() != c.val || prueba.Filas() != c.test {
+			t.Errorf("%d filas con validacion %g y prueba %g: %d, %d, %d, se esperaba %d, %d, %d",
+				c.filas, c.validacion, c.prueba, entrenamiento.Filas(), validacion.Filas(), prueba.Filas(),
+				c.entrenamiento, c.val, c.test)
+			continue
+		}
+
+		// cada muestra queda exactamente en una particion
+		todas := append(append(filasDe(t, entrenamiento), filasDe(t, validacion)...), filasDe(t, prueba)...)
+		slices.Sort(todas)
+		for i, n := range todas {
+			if n != i {
+				t.Errorf("%d filas: las particiones tienen %v", c.filas, todas)
+				break
+			}
+		}
+	}
+}
+
+func TestPliegues(t *testing.T) {
+	for _, c := range []struct{ filas, k int }{{10, 5}, {11, 3}, {5, 5}} {
+		X, Yd := datosNumerados(c.filas)
+		pliegues := Pliegues(X, Yd, c.k)
+		if len(pliegues) != c.k {
+			t.Fatalf("%d pliegues, se esperaban %d", len(pliegues), c.k)
+		}
+
+		var probadas []int
+		for _, p := range pliegues {
+			prueba := filasDe(t, p.Prueba)
+			entrenamiento := filasDe(t, p.Entrenamiento)
+			// los tamaños de prueba difieren a lo mucho en uno
+			if len(prueba) < c.filas/c.k || len(prueba) > c.filas/c.k+1 {
+				t.Errorf("%d filas en %d pliegues: un pliegue prueba con %d", c.filas, c.k, len(prueba))
+			}
+			if len(prueba)+len(entrenamiento) != c.filas {
+				t.Errorf("el pliegue tiene %d de prueba y %d de entrenamiento, se esperaban %d en total",
+					len(prueba), len(entrenamiento), c.filas)
+			}
+			for _, n := range prueba {
+				if slices.Contains(entrenamiento, n) {
+					t.Errorf("la fila %d esta en prueba y en entrenamiento", n)
+				}
+			}
+			probadas = append(probadas, prueba...)
+		}
+
+		// cada fila se prueba una sola vez
+		slices.Sort(probadas)
+		for i, n := range probadas {
+			if n != i {
+				t.Errorf("%d filas en %d pliegues: se probaron %v", c.filas, c.k, probadas)
+				break
+			}
+		}
+	}
+}
+
+func TestCalcularMetricas(t *testing.T) {
+	m := CalcularMetricas([]float64{1, 2, 3, 4}, []float64{2, 2, 3, 2})
+	if m.MAE != 0.75 || m.RMSE != math.Sqrt(5.0/4) || m.R2 != 0 {
+		t.Errorf("metricas %+v", m)
+	}
+	if m := CalcularMetricas([]float64{1, 2, 3}, []float64{1, 2, 3}); m.MAE != 0 || m.RMSE != 0 || m.R2 != 1 {
+		t.Errorf("una prediccion perfecta dio %+v", m)
+	}
+	// sin varianza en los deseados R² no existe
+	if m := CalcularMetricas([]float64{5, 5}, []float64{4, 6}); !math.IsNaN(m.R2) {
+		t.Errorf("R² con valores constantes = %g, se esperaba NaN", m.R2)
+	}
+}